@@ -1,18 +1,33 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/dew-77/mattermost-vote-system/internal/api"
 	"github.com/dew-77/mattermost-vote-system/internal/app"
+	"github.com/dew-77/mattermost-vote-system/internal/cli"
 	"github.com/dew-77/mattermost-vote-system/internal/config"
 	"github.com/dew-77/mattermost-vote-system/internal/mattermost"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
 	"github.com/dew-77/mattermost-vote-system/internal/repository"
 	"github.com/dew-77/mattermost-vote-system/pkg/logger"
 )
 
 func main() {
+	// С аргументами бинарник выполняет служебную команду, например polls list
+	if len(os.Args) > 1 {
+		if err := cli.Run(os.Args[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Ошибка:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		panic("Failed to load config: " + err.Error())
@@ -32,12 +47,46 @@ func main() {
 	
 	application := app.NewApp(cfg, log, mmClient, repo)
 	
+	apiServer := api.NewServer(&cfg.API, log, repo, application)
+	if apiServer.Enabled() {
+		go func() {
+			if err := apiServer.Start(); err != nil {
+				log.WithError(err).Error("HTTP API stopped")
+			}
+		}()
+	} else {
+		log.Info("HTTP API disabled: no API tokens configured")
+	}
+	
+	monitoringServer := monitoring.NewServer(&cfg.Monitoring, log,
+		monitoring.Check{Name: "tarantool", Run: repo.HealthCheck},
+		monitoring.Check{Name: "mattermost", Run: mmClient.Ping},
+		monitoring.Check{Name: "websocket", Run: application.CheckWebSocket},
+	)
+	if monitoringServer.Enabled() {
+		go func() {
+			if err := monitoringServer.Start(); err != nil {
+				log.WithError(err).Error("Monitoring server stopped")
+			}
+		}()
+	}
+	
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	
 	go func() {
 		<-c
 		log.Info("Shutting down...")
+		
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := apiServer.Shutdown(ctx); err != nil {
+			log.WithError(err).Warn("Failed to shut down HTTP API")
+		}
+		if err := monitoringServer.Shutdown(ctx); err != nil {
+			log.WithError(err).Warn("Failed to shut down monitoring server")
+		}
+		
 		os.Exit(0)
 	}()
 	
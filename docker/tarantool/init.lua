@@ -3,6 +3,7 @@ box.cfg {
     memtx_memory = 128 * 1024 * 1024, -- 128 MB
     wal_dir = '/var/lib/tarantool',
     memtx_dir = '/var/lib/tarantool',
+    vinyl_dir = '/var/lib/tarantool',
     force_recovery = true
 }
 
@@ -26,16 +27,50 @@ local polls = box.schema.space.create('polls', {
     format = {
         {name = 'id', type = 'string'},
         {name = 'title', type = 'string'},
-        {name = 'options', type = 'array'},
+        {name = 'options', type = 'array'}, -- {{id, text}, ...}
         {name = 'creator_id', type = 'string'},
         {name = 'channel_id', type = 'string'},
         {name = 'created_at', type = 'datetime'},
         {name = 'finished_at', type = 'datetime', is_nullable = true},
-        {name = 'is_finished', type = 'boolean'},
-        {name = 'post_id', type = 'string'}
+        {name = 'status', type = 'string'}, -- draft | scheduled | open | closed
+        {name = 'post_id', type = 'string'},
+        {name = 'closes_at', type = 'datetime', is_nullable = true},
+        {name = 'eligible_voters', type = 'array', is_nullable = true},
+        {name = 'eligible_groups', type = 'array', is_nullable = true},
+        {name = 'quorum', type = 'array', is_nullable = true}, -- {count, percent}
+        {name = 'threshold', type = 'string', is_nullable = true},
+        {name = 'electorate', type = 'unsigned', is_nullable = true},
+        {name = 'tie_break', type = 'string', is_nullable = true},
+        {name = 'tie_seed', type = 'integer', is_nullable = true},
+        {name = 'casting_vote', type = 'integer', is_nullable = true},
+        {name = 'kind', type = 'string', is_nullable = true},
+        {name = 'credits', type = 'unsigned', is_nullable = true},
+        {name = 'seats', type = 'unsigned', is_nullable = true},
+        {name = 'opens_at', type = 'datetime', is_nullable = true},
+        {name = 'reminders', type = 'array', is_nullable = true}, -- секунды до завершения
+        {name = 'reminder_mode', type = 'string', is_nullable = true},
+        {name = 'reminders_sent', type = 'array', is_nullable = true},
+        {name = 'anonymous', type = 'boolean', is_nullable = true}
     }
 })
 
+-- Migrate is_finished flag to status: earlier versions stored a boolean in field 8
+box.once('polls_status_v1', function()
+    local format = polls:format()
+    if format[8] == nil or format[8].name ~= 'is_finished' then
+        return
+    end
+    format[8] = {name = 'status', type = 'any'}
+    polls:format(format)
+    for _, poll in polls:pairs() do
+        if type(poll[8]) == 'boolean' then
+            polls:update(poll[1], {{'=', 8, poll[8] and 'closed' or 'open'}})
+        end
+    end
+    format[8].type = 'string'
+    polls:format(format)
+end)
+
 -- Create indexes for polls
 polls:create_index('primary', {
     type = 'hash',
@@ -63,8 +98,12 @@ local votes = box.schema.space.create('votes', {
     format = {
         {name = 'poll_id', type = 'string'},
         {name = 'user_id', type = 'string'},
-        {name = 'option_idx', type = 'number'},
-        {name = 'voted_at', type = 'datetime'}
+        {name = 'option_idx', type = 'number'}, -- id варианта, а не позиция
+        {name = 'voted_at', type = 'datetime'},
+        {name = 'weight', type = 'unsigned', is_nullable = true},
+        {name = 'allocation', type = 'array', is_nullable = true}, -- {{option_id, votes}, ...}
+        {name = 'ranking', type = 'array', is_nullable = true}, -- {option_id, ...} в порядке предпочтения
+        {name = 'approved', type = 'array', is_nullable = true} -- {option_id, ...} одобренные варианты
     }
 })
 
@@ -96,4 +135,130 @@ votes:create_index('user_poll', {
     if_not_exists = true
 })
 
+-- Create space for per-poll vote weights
+local weights = box.schema.space.create('weights', {
+    if_not_exists = true,
+    format = {
+        {name = 'poll_id', type = 'string'},
+        {name = 'user_id', type = 'string'},
+        {name = 'weight', type = 'unsigned'}
+    }
+})
+
+-- Create indexes for weights
+weights:create_index('primary', {
+    type = 'tree',
+    parts = {'poll_id', 'user_id'},
+    if_not_exists = true
+})
+
+-- Create space for audit trail
+local audit = box.schema.space.create('audit', {
+    if_not_exists = true,
+    format = {
+        {name = 'id', type = 'string'},
+        {name = 'poll_id', type = 'string'},
+        {name = 'user_id', type = 'string'},
+        {name = 'action', type = 'string'},
+        {name = 'details', type = 'string'},
+        {name = 'created_at', type = 'datetime'}
+    }
+})
+
+-- Create indexes for audit trail
+audit:create_index('primary', {
+    type = 'hash',
+    parts = {'id'},
+    if_not_exists = true
+})
+
+audit:create_index('poll', {
+    type = 'tree',
+    parts = {'poll_id'},
+    unique = false,
+    if_not_exists = true
+})
+
+-- Create space for recurring poll schedules
+local schedules = box.schema.space.create('schedules', {
+    if_not_exists = true,
+    format = {
+        {name = 'id', type = 'string'},
+        {name = 'creator_id', type = 'string'},
+        {name = 'channel_id', type = 'string'},
+        {name = 'spec', type = 'string'}, -- cron: минуты часы день месяц день_недели
+        {name = 'args', type = 'array'}, -- аргументы команды create
+        {name = 'created_at', type = 'datetime'},
+        {name = 'next_run_at', type = 'datetime'},
+        {name = 'last_run_at', type = 'datetime', is_nullable = true},
+        {name = 'last_poll', type = 'string', is_nullable = true}
+    }
+})
+
+-- Create indexes for schedules
+schedules:create_index('primary', {
+    type = 'hash',
+    parts = {'id'},
+    if_not_exists = true
+})
+
+schedules:create_index('channel', {
+    type = 'tree',
+    parts = {'channel_id'},
+    unique = false,
+    if_not_exists = true
+})
+
+-- Create space for webhook deliveries that failed after all retries
+local dead_letters = box.schema.space.create('dead_letters', {
+    if_not_exists = true,
+    format = {
+        {name = 'id', type = 'string'},
+        {name = 'url', type = 'string'},
+        {name = 'event_type', type = 'string'},
+        {name = 'payload', type = 'string'}, -- подписанное JSON-тело запроса
+        {name = 'attempts', type = 'unsigned'},
+        {name = 'last_error', type = 'string'},
+        {name = 'failed_at', type = 'datetime'}
+    }
+})
+
+-- Create indexes for dead letters
+dead_letters:create_index('primary', {
+    type = 'hash',
+    parts = {'id'},
+    if_not_exists = true
+})
+
+-- Create disk-based space for archived polls: they leave the memtx arena
+-- but their results stay available read-only
+local polls_archive = box.schema.space.create('polls_archive', {
+    engine = 'vinyl',
+    if_not_exists = true,
+    format = {
+        {name = 'id', type = 'string'},
+        {name = 'channel_id', type = 'string'},
+        {name = 'finished_at', type = 'datetime', is_nullable = true},
+        {name = 'archived_at', type = 'datetime'},
+        {name = 'poll', type = 'array'}, -- кортеж из polls
+        {name = 'votes', type = 'array'}, -- кортежи из votes
+        {name = 'weights', type = 'array'}, -- {{user_id, weight}, ...}
+        {name = 'audit', type = 'array', is_nullable = true} -- кортежи из audit
+    }
+})
+
+-- Create indexes for archived polls
+polls_archive:create_index('primary', {
+    type = 'tree',
+    parts = {'id'},
+    if_not_exists = true
+})
+
+polls_archive:create_index('channel', {
+    type = 'tree',
+    parts = {'channel_id'},
+    unique = false,
+    if_not_exists = true
+})
+
 print('Tarantool initialized successfully')
\ No newline at end of file
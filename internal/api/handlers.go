@@ -0,0 +1,381 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+const maxBodySize = 1 << 20
+
+func (s *Server) handleListPolls(w http.ResponseWriter, r *http.Request) {
+	polls, err := s.repository.ListPolls()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list polls")
+		writeError(w, http.StatusInternalServerError, "failed to list polls")
+		return
+	}
+	
+	channelID := r.URL.Query().Get("channel_id")
+	status := r.URL.Query().Get("status")
+	
+	filtered := make([]models.Poll, 0, len(polls))
+	for _, poll := range polls {
+		if channelID != "" && poll.ChannelID != channelID {
+			continue
+		}
+		if status != "" && poll.Status != status {
+			continue
+		}
+		filtered = append(filtered, poll)
+	}
+	
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+	
+	writeJSON(w, http.StatusOK, filtered)
+}
+
+func (s *Server) handleCreatePoll(w http.ResponseWriter, r *http.Request) {
+	var poll models.Poll
+	if err := decodeBody(r, &poll); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	if err := preparePoll(&poll); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	poll, err := s.service.CreatePoll(poll)
+	if errors.Is(err, models.ErrNotChannelMember) {
+		writeError(w, http.StatusForbidden, "creator is not a member of the channel")
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create poll via API")
+		writeError(w, http.StatusInternalServerError, "failed to create poll")
+		return
+	}
+	
+	writeJSON(w, http.StatusCreated, poll)
+}
+
+// preparePoll проверяет голосование из запроса и заполняет поля,
+// которые задаёт сервер: ID, время создания и идентификаторы вариантов.
+func preparePoll(poll *models.Poll) error {
+	if poll.Title == "" {
+		return errors.New("title is required")
+	}
+	if !model.IsValidId(poll.ChannelID) {
+		return errors.New("channel_id must be a Mattermost channel ID")
+	}
+	if !model.IsValidId(poll.CreatorID) {
+		return errors.New("creator_id must be a Mattermost user ID")
+	}
+	for _, voterID := range poll.EligibleVoters {
+		if !model.IsValidId(voterID) {
+			return fmt.Errorf("eligible voter %q is not a Mattermost user ID", voterID)
+		}
+	}
+	for _, groupID := range poll.EligibleGroups {
+		if !model.IsValidId(groupID) {
+			return fmt.Errorf("eligible group %q is not a Mattermost group ID", groupID)
+		}
+	}
+	
+	switch poll.Kind {
+	case models.PollKindStandard, models.PollKindApproval:
+	case models.PollKindProposal:
+		poll.Options = models.NewProposalOptions()
+	case models.PollKindQuadratic:
+		if poll.Credits <= 0 {
+			return errors.New("credits must be positive for quadratic polls")
+		}
+	case models.PollKindSTV:
+		if poll.Seats <= 0 {
+			poll.Seats = 1
+		}
+		if poll.Seats >= len(poll.Options) {
+			return errors.New("seats must be less than the number of options")
+		}
+	default:
+		return fmt.Errorf("unknown poll kind %q", poll.Kind)
+	}
+	
+	if len(poll.Options) < 2 {
+		return errors.New("at least 2 options are required")
+	}
+	
+	texts := make([]string, len(poll.Options))
+	for i, option := range poll.Options {
+		if option.Text == "" {
+			return fmt.Errorf("option %d has empty text", i+1)
+		}
+		texts[i] = option.Text
+	}
+	
+	if err := checkQuorum(poll.Quorum); err != nil {
+		return err
+	}
+	if poll.Threshold != "" {
+		threshold, err := models.ParseThreshold(poll.Threshold)
+		if err != nil {
+			return err
+		}
+		poll.Threshold = threshold
+	}
+	if poll.TieBreak != "" {
+		tieBreak, err := models.ParseTieBreak(poll.TieBreak)
+		if err != nil {
+			return err
+		}
+		poll.TieBreak = tieBreak
+	}
+	
+	now := time.Now()
+	if !poll.ClosesAt.IsZero() && !poll.ClosesAt.After(now) {
+		return errors.New("closes_at must be in the future")
+	}
+	if !poll.OpensAt.IsZero() && !poll.ClosesAt.IsZero() && !poll.ClosesAt.After(poll.OpensAt) {
+		return errors.New("closes_at must be after opens_at")
+	}
+	
+	poll.ID = uuid.New().String()[:8]
+	poll.Options = models.NewOptions(texts)
+	poll.CreatedAt = now
+	poll.FinishedAt = time.Time{}
+	poll.PostID = ""
+	poll.Status = models.PollStatusDraft
+	poll.CastingVote = nil
+	poll.RemindersSent = nil
+	// Зерно жребия и число участников задаёт сервер: иначе клиент мог бы
+	// выбрать исход жребия или подогнать кворум и явку
+	poll.TieSeed = 0
+	if poll.TieBreak == models.TieBreakRandom {
+		poll.TieSeed = rand.Int63()
+	}
+	poll.Electorate = 0
+	
+	if poll.IsBinding() && poll.Threshold == "" {
+		poll.Threshold = models.ThresholdMajority
+	}
+	
+	return nil
+}
+
+// checkQuorum применяет те же границы, что и models.ParseQuorum для команд
+// чата: число голосов больше нуля либо доля от 0 до 100 процентов.
+func checkQuorum(quorum models.Quorum) error {
+	switch {
+	case quorum.Count == 0 && quorum.Percent == 0:
+		return nil
+	case quorum.Count != 0 && quorum.Percent != 0:
+		return errors.New("quorum must set either count or percent, not both")
+	case quorum.Count < 0:
+		return errors.New("quorum count must be positive")
+	case quorum.Count == 0 && (quorum.Percent < 0 || quorum.Percent > 100):
+		return errors.New("quorum percent must be greater than 0 and at most 100")
+	}
+	return nil
+}
+
+func (s *Server) handleGetPoll(w http.ResponseWriter, r *http.Request) {
+	poll, ok := s.getPoll(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, poll)
+}
+
+func (s *Server) handleListVotes(w http.ResponseWriter, r *http.Request) {
+	poll, ok := s.getPoll(w, r)
+	if !ok {
+		return
+	}
+	
+	if poll.Anonymous {
+		writeError(w, http.StatusForbidden, "votes of anonymous polls are not available")
+		return
+	}
+	
+	votes, err := s.repository.GetVotes(poll.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get votes")
+		writeError(w, http.StatusInternalServerError, "failed to get votes")
+		return
+	}
+	
+	if votes == nil {
+		votes = []models.Vote{}
+	}
+	writeJSON(w, http.StatusOK, votes)
+}
+
+func (s *Server) handleCastVote(w http.ResponseWriter, r *http.Request) {
+	poll, ok := s.getPoll(w, r)
+	if !ok {
+		return
+	}
+	
+	var vote models.Vote
+	if err := decodeBody(r, &vote); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	switch {
+	case poll.IsClosed():
+		writeError(w, http.StatusConflict, "poll is finished")
+		return
+	case !poll.IsOpen():
+		writeError(w, http.StatusConflict, "poll is not open yet")
+		return
+	}
+	
+	if err := prepareVote(poll, &vote); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	
+	vote, err := s.service.CastVote(poll, vote)
+	switch {
+	case errors.Is(err, models.ErrNotChannelMember), errors.Is(err, models.ErrNotEligible):
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	case errors.Is(err, repository.ErrBudgetExceeded):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case errors.Is(err, repository.ErrPollFinished), errors.Is(err, repository.ErrPollNotOpen):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		s.logger.WithError(err).Error("Failed to save vote via API")
+		writeError(w, http.StatusInternalServerError, "failed to save vote")
+		return
+	}
+	
+	writeJSON(w, http.StatusCreated, vote)
+}
+
+// prepareVote проверяет бюллетень по типу голосования. Варианты задаются
+// постоянными идентификаторами (option_id), а не номерами из сообщения.
+func prepareVote(poll models.Poll, vote *models.Vote) error {
+	if !model.IsValidId(vote.UserID) {
+		return errors.New("user_id must be a Mattermost user ID")
+	}
+	
+	vote.PollID = poll.ID
+	vote.VotedAt = time.Now()
+	
+	switch {
+	case poll.IsQuadratic():
+		if len(vote.Allocation) == 0 {
+			return errors.New("allocation is required for quadratic polls")
+		}
+		for optionID, votes := range vote.Allocation {
+			if _, ok := poll.OptionByID(optionID); !ok {
+				return fmt.Errorf("unknown option %d", optionID)
+			}
+			if votes < 1 {
+				return errors.New("allocation votes must be positive")
+			}
+		}
+		if vote.Cost() > poll.Credits {
+			return fmt.Errorf("%w: cost %d of %d", repository.ErrBudgetExceeded, vote.Cost(), poll.Credits)
+		}
+		vote.OptionID = models.NoOption
+		vote.Ranking, vote.Approved = nil, nil
+	case poll.IsSTV():
+		if err := checkOptionSet(poll, vote.Ranking); err != nil {
+			return fmt.Errorf("ranking: %w", err)
+		}
+		vote.OptionID = models.NoOption
+		vote.Allocation, vote.Approved = nil, nil
+	case poll.IsApproval():
+		if err := checkOptionSet(poll, vote.Approved); err != nil {
+			return fmt.Errorf("approved: %w", err)
+		}
+		sort.Ints(vote.Approved)
+		vote.OptionID = models.NoOption
+		vote.Allocation, vote.Ranking = nil, nil
+	default:
+		if _, ok := poll.OptionByID(vote.OptionID); !ok {
+			return fmt.Errorf("unknown option %d", vote.OptionID)
+		}
+		vote.Allocation, vote.Ranking, vote.Approved = nil, nil, nil
+	}
+	
+	return nil
+}
+
+func checkOptionSet(poll models.Poll, optionIDs []int) error {
+	if len(optionIDs) == 0 {
+		return errors.New("at least one option is required")
+	}
+	
+	seen := make(map[int]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if _, ok := poll.OptionByID(optionID); !ok {
+			return fmt.Errorf("unknown option %d", optionID)
+		}
+		if seen[optionID] {
+			return fmt.Errorf("option %d listed twice", optionID)
+		}
+		seen[optionID] = true
+	}
+	return nil
+}
+
+func (s *Server) handleResults(w http.ResponseWriter, r *http.Request) {
+	results, err := s.repository.GetPollResults(r.PathValue("id"))
+	if errors.Is(err, repository.ErrPollNotFound) {
+		writeError(w, http.StatusNotFound, "poll not found")
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get poll results")
+		writeError(w, http.StatusInternalServerError, "failed to get poll results")
+		return
+	}
+	
+	if results.Poll.Anonymous {
+		results.Voters = nil
+	}
+	
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) getPoll(w http.ResponseWriter, r *http.Request) (models.Poll, bool) {
+	poll, err := s.repository.GetPoll(r.PathValue("id"))
+	if errors.Is(err, repository.ErrPollNotFound) {
+		writeError(w, http.StatusNotFound, "poll not found")
+		return models.Poll{}, false
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get poll")
+		writeError(w, http.StatusInternalServerError, "failed to get poll")
+		return models.Poll{}, false
+	}
+	return poll, true
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/dew-77/mattermost-vote-system/internal/config"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// PollService создаёт голосования и принимает голоса по тем же правилам,
+// что и команды чата: проверяет участие в канале, списки участников
+// и веса групп. Реализуется приложением бота.
+type PollService interface {
+	CreatePoll(poll models.Poll) (models.Poll, error)
+	CastVote(poll models.Poll, vote models.Vote) (models.Vote, error)
+}
+
+// Server — HTTP API поверх PollRepository.
+type Server struct {
+	repository repository.PollRepository
+	service    PollService
+	logger     *logrus.Logger
+	tokens     [][]byte
+	server     *http.Server
+}
+
+func NewServer(cfg *config.APIConfig, logger *logrus.Logger, repo repository.PollRepository, service PollService) *Server {
+	s := &Server{
+		repository: repo,
+		service:    service,
+		logger:     logger,
+	}
+	
+	for _, token := range cfg.Tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			s.tokens = append(s.tokens, []byte(token))
+		}
+	}
+	
+	s.server = &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	
+	return s
+}
+
+// Enabled сообщает, настроен ли хотя бы один токен доступа.
+func (s *Server) Enabled() bool {
+	return len(s.tokens) > 0
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	
+	mux.HandleFunc("GET /api/v1/openapi.yaml", s.handleOpenAPI)
+	
+	mux.Handle("GET /api/v1/polls", s.authenticated(s.handleListPolls))
+	mux.Handle("POST /api/v1/polls", s.authenticated(s.handleCreatePoll))
+	mux.Handle("GET /api/v1/polls/{id}", s.authenticated(s.handleGetPoll))
+	mux.Handle("GET /api/v1/polls/{id}/votes", s.authenticated(s.handleListVotes))
+	mux.Handle("POST /api/v1/polls/{id}/votes", s.authenticated(s.handleCastVote))
+	mux.Handle("GET /api/v1/polls/{id}/results", s.authenticated(s.handleResults))
+	
+	return mux
+}
+
+func (s *Server) Start() error {
+	s.logger.WithField("listen", s.server.Addr).Info("Starting HTTP API")
+	
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || !s.validToken(token) {
+			writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+			return
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) validToken(token string) bool {
+	valid := false
+	for _, expected := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), expected) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(openAPISpec)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
\ No newline at end of file
@@ -0,0 +1,456 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/sirupsen/logrus"
+	"github.com/dew-77/mattermost-vote-system/internal/config"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+const testToken = "secret"
+
+// fakeRepository хранит голосования и голоса в памяти. Методы, которые API
+// не вызывает, остаются от встроенного nil-интерфейса и паникуют.
+type fakeRepository struct {
+	repository.PollRepository
+	polls map[string]models.Poll
+	votes map[string][]models.Vote
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		polls: make(map[string]models.Poll),
+		votes: make(map[string][]models.Vote),
+	}
+}
+
+func (f *fakeRepository) GetPoll(pollID string) (models.Poll, error) {
+	poll, ok := f.polls[pollID]
+	if !ok {
+		return models.Poll{}, repository.ErrPollNotFound
+	}
+	return poll, nil
+}
+
+func (f *fakeRepository) ListPolls() ([]models.Poll, error) {
+	polls := make([]models.Poll, 0, len(f.polls))
+	for _, poll := range f.polls {
+		polls = append(polls, poll)
+	}
+	return polls, nil
+}
+
+func (f *fakeRepository) GetVotes(pollID string) ([]models.Vote, error) {
+	return f.votes[pollID], nil
+}
+
+func (f *fakeRepository) GetPollResults(pollID string) (models.PollResults, error) {
+	poll, err := f.GetPoll(pollID)
+	if err != nil {
+		return models.PollResults{}, err
+	}
+	
+	results := models.PollResults{
+		Poll:    poll,
+		Results: make(map[int]int),
+		Voters:  make(map[string]int),
+	}
+	for _, vote := range f.votes[pollID] {
+		results.Results[vote.OptionID]++
+		results.Voters[vote.UserID] = vote.OptionID
+		results.TotalVotes++
+	}
+	return results, nil
+}
+
+// fakeService повторяет правила приложения: автор и голосующие должны
+// состоять в канале, а голосующие — ещё и в списке участников, если он задан.
+type fakeService struct {
+	repo    *fakeRepository
+	members map[string]bool
+	weights map[string]int
+}
+
+func (f *fakeService) CreatePoll(poll models.Poll) (models.Poll, error) {
+	if !f.members[poll.CreatorID] {
+		return models.Poll{}, models.ErrNotChannelMember
+	}
+	
+	poll.Status = models.PollStatusOpen
+	f.repo.polls[poll.ID] = poll
+	return poll, nil
+}
+
+func (f *fakeService) CastVote(poll models.Poll, vote models.Vote) (models.Vote, error) {
+	if !f.members[vote.UserID] {
+		return vote, models.ErrNotChannelMember
+	}
+	if poll.HasVoterList() && !slices.Contains(poll.EligibleVoters, vote.UserID) {
+		return vote, models.ErrNotEligible
+	}
+	
+	vote.Weight = f.weights[vote.UserID]
+	f.repo.votes[poll.ID] = append(f.repo.votes[poll.ID], vote)
+	return vote, nil
+}
+
+type testEnv struct {
+	handler   http.Handler
+	repo      *fakeRepository
+	service   *fakeService
+	channelID string
+	member    string
+	outsider  string
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	
+	env := &testEnv{
+		repo:      newFakeRepository(),
+		channelID: model.NewId(),
+		member:    model.NewId(),
+		outsider:  model.NewId(),
+	}
+	env.service = &fakeService{
+		repo:    env.repo,
+		members: map[string]bool{env.member: true},
+		weights: map[string]int{},
+	}
+	
+	cfg := &config.APIConfig{Listen: ":0", Tokens: []string{testToken}}
+	env.handler = NewServer(cfg, logger, env.repo, env.service).Handler()
+	return env
+}
+
+func (e *testEnv) addPoll(poll models.Poll) models.Poll {
+	if poll.ID == "" {
+		poll.ID = "poll0001"
+	}
+	if poll.Title == "" {
+		poll.Title = "Где обедаем?"
+	}
+	if poll.Options == nil {
+		poll.Options = models.NewOptions([]string{"Пицца", "Суши"})
+	}
+	if poll.Status == "" {
+		poll.Status = models.PollStatusOpen
+	}
+	poll.ChannelID = e.channelID
+	poll.CreatorID = e.member
+	poll.CreatedAt = time.Now()
+	e.repo.polls[poll.ID] = poll
+	return poll
+}
+
+func (e *testEnv) do(t *testing.T, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	
+	rec := httptest.NewRecorder()
+	e.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	env := newTestEnv(t)
+	env.addPoll(models.Poll{})
+	
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"missing token", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"wrong scheme", "Token " + testToken, http.StatusUnauthorized},
+		{"valid token", "Bearer " + testToken, http.StatusOK},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/polls/poll0001", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			
+			rec := httptest.NewRecorder()
+			env.handler.ServeHTTP(rec, req)
+			
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestOpenAPIDoesNotRequireToken(t *testing.T) {
+	env := newTestEnv(t)
+	
+	rec := env.do(t, http.MethodGet, "/api/v1/openapi.yaml", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestCreatePoll(t *testing.T) {
+	env := newTestEnv(t)
+	
+	body := `{"title": "Где обедаем?", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `",
+		"options": [{"text": "Пицца"}, {"text": "Суши"}]}`
+	rec := env.do(t, http.MethodPost, "/api/v1/polls", testToken, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	
+	var poll models.Poll
+	decodeResponse(t, rec, &poll)
+	
+	if poll.ID == "" {
+		t.Fatal("poll ID is empty")
+	}
+	if poll.Status != models.PollStatusOpen {
+		t.Errorf("status = %q, want %q", poll.Status, models.PollStatusOpen)
+	}
+	if len(poll.Options) != 2 || poll.Options[0].ID != 0 || poll.Options[1].ID != 1 {
+		t.Errorf("options = %+v, want IDs 0 and 1", poll.Options)
+	}
+	if _, ok := env.repo.polls[poll.ID]; !ok {
+		t.Error("poll was not stored")
+	}
+}
+
+func TestCreatePollRejectsNonMemberCreator(t *testing.T) {
+	env := newTestEnv(t)
+	
+	body := `{"title": "Где обедаем?", "channel_id": "` + env.channelID + `", "creator_id": "` + env.outsider + `",
+		"options": [{"text": "Пицца"}, {"text": "Суши"}]}`
+	rec := env.do(t, http.MethodPost, "/api/v1/polls", testToken, body)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+}
+
+func TestCreatePollBadRequest(t *testing.T) {
+	env := newTestEnv(t)
+	
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed JSON", `{"title": `},
+		{"unknown field", `{"title": "Т", "color": "red"}`},
+		{"missing title", `{"channel_id": "` + env.channelID + `"}`},
+		{"invalid creator", `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "admin", "options": [{"text": "А"}, {"text": "Б"}]}`},
+		{"negative quorum count", `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `", "options": [{"text": "А"}, {"text": "Б"}], "quorum": {"count": -1}}`},
+		{"negative quorum percent", `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `", "options": [{"text": "А"}, {"text": "Б"}], "quorum": {"percent": -5}}`},
+		{"quorum percent above 100", `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `", "options": [{"text": "А"}, {"text": "Б"}], "quorum": {"percent": 150}}`},
+		{"single option", `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `", "options": [{"text": "А"}]}`},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := env.do(t, http.MethodPost, "/api/v1/polls", testToken, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+		})
+	}
+	
+	if len(env.repo.polls) != 0 {
+		t.Errorf("stored %d polls from bad requests", len(env.repo.polls))
+	}
+}
+
+func TestCastVote(t *testing.T) {
+	env := newTestEnv(t)
+	env.addPoll(models.Poll{})
+	env.service.weights[env.member] = 3
+	
+	body := `{"user_id": "` + env.member + `", "option_id": 1, "weight": 100}`
+	rec := env.do(t, http.MethodPost, "/api/v1/polls/poll0001/votes", testToken, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	
+	var vote models.Vote
+	decodeResponse(t, rec, &vote)
+	
+	if vote.PollID != "poll0001" || vote.UserID != env.member || vote.OptionID != 1 {
+		t.Errorf("vote = %+v", vote)
+	}
+	if vote.Weight != 3 {
+		t.Errorf("weight = %d, want the group weight 3", vote.Weight)
+	}
+	if len(env.repo.votes["poll0001"]) != 1 {
+		t.Errorf("stored %d votes, want 1", len(env.repo.votes["poll0001"]))
+	}
+}
+
+func TestCastVoteErrors(t *testing.T) {
+	env := newTestEnv(t)
+	env.addPoll(models.Poll{})
+	env.addPoll(models.Poll{ID: "closed01", Status: models.PollStatusClosed})
+	env.addPoll(models.Poll{ID: "private1", EligibleVoters: []string{model.NewId()}})
+	
+	tests := []struct {
+		name   string
+		pollID string
+		body   string
+		status int
+	}{
+		{"unknown poll", "missing1", `{"user_id": "` + env.member + `", "option_id": 0}`, http.StatusNotFound},
+		{"malformed JSON", "poll0001", `{"user_id": `, http.StatusBadRequest},
+		{"invalid user", "poll0001", `{"user_id": "bob", "option_id": 0}`, http.StatusBadRequest},
+		{"unknown option", "poll0001", `{"user_id": "` + env.member + `", "option_id": 7}`, http.StatusBadRequest},
+		{"not a channel member", "poll0001", `{"user_id": "` + env.outsider + `", "option_id": 0}`, http.StatusForbidden},
+		{"not on voter list", "private1", `{"user_id": "` + env.member + `", "option_id": 0}`, http.StatusForbidden},
+		{"finished poll", "closed01", `{"user_id": "` + env.member + `", "option_id": 0}`, http.StatusConflict},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := env.do(t, http.MethodPost, "/api/v1/polls/"+tt.pollID+"/votes", testToken, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+	
+	for pollID, votes := range env.repo.votes {
+		if len(votes) != 0 {
+			t.Errorf("stored %d votes in %s from rejected requests", len(votes), pollID)
+		}
+	}
+}
+
+func TestResults(t *testing.T) {
+	env := newTestEnv(t)
+	env.addPoll(models.Poll{})
+	env.repo.votes["poll0001"] = []models.Vote{
+		{PollID: "poll0001", UserID: env.member, OptionID: 1},
+	}
+	
+	rec := env.do(t, http.MethodGet, "/api/v1/polls/poll0001/results", testToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	
+	var results models.PollResults
+	decodeResponse(t, rec, &results)
+	
+	if results.TotalVotes != 1 || results.Results[1] != 1 {
+		t.Errorf("results = %+v, want one vote for option 1", results.Results)
+	}
+	if results.Voters[env.member] != 1 {
+		t.Errorf("voters = %+v, want %s for option 1", results.Voters, env.member)
+	}
+}
+
+func TestResultsHideAnonymousVoters(t *testing.T) {
+	env := newTestEnv(t)
+	env.addPoll(models.Poll{Anonymous: true})
+	env.repo.votes["poll0001"] = []models.Vote{
+		{PollID: "poll0001", UserID: env.member, OptionID: 0},
+	}
+	
+	rec := env.do(t, http.MethodGet, "/api/v1/polls/poll0001/results", testToken, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	
+	var results models.PollResults
+	decodeResponse(t, rec, &results)
+	
+	if len(results.Voters) != 0 {
+		t.Errorf("voters = %+v, want none for an anonymous poll", results.Voters)
+	}
+}
+
+func TestUnknownPoll(t *testing.T) {
+	env := newTestEnv(t)
+	
+	for _, path := range []string{
+		"/api/v1/polls/missing1",
+		"/api/v1/polls/missing1/votes",
+		"/api/v1/polls/missing1/results",
+	} {
+		t.Run(path, func(t *testing.T) {
+			rec := env.do(t, http.MethodGet, path, testToken, "")
+			if rec.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
+
+func TestCreatePollNormalizesTieBreak(t *testing.T) {
+	env := newTestEnv(t)
+	
+	body := `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `",
+		"options": [{"text": "А"}, {"text": "Б"}], "tie_break": "first"}`
+	rec := env.do(t, http.MethodPost, "/api/v1/polls", testToken, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	
+	var poll models.Poll
+	decodeResponse(t, rec, &poll)
+	
+	if poll.TieBreak != models.TieBreakEarliest {
+		t.Errorf("tie_break = %q, want %q", poll.TieBreak, models.TieBreakEarliest)
+	}
+}
+
+func TestCreatePollIgnoresServerFields(t *testing.T) {
+	env := newTestEnv(t)
+	
+	body := `{"title": "Т", "channel_id": "` + env.channelID + `", "creator_id": "` + env.member + `",
+		"options": [{"text": "А"}, {"text": "Б"}], "tie_break": "draw", "tie_seed": 42, "electorate": 1}`
+	rec := env.do(t, http.MethodPost, "/api/v1/polls", testToken, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body.String())
+	}
+	
+	var poll models.Poll
+	decodeResponse(t, rec, &poll)
+	
+	if poll.TieBreak != models.TieBreakRandom {
+		t.Errorf("tie_break = %q, want %q", poll.TieBreak, models.TieBreakRandom)
+	}
+	if poll.TieSeed == 0 || poll.TieSeed == 42 {
+		t.Errorf("tie_seed = %d, want a seed generated by the server", poll.TieSeed)
+	}
+	if poll.Electorate != 0 {
+		t.Errorf("electorate = %d, want it left for the server to count", poll.Electorate)
+	}
+}
\ No newline at end of file
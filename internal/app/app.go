@@ -2,29 +2,66 @@ package app
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/sirupsen/logrus"
 	"github.com/dew-77/mattermost-vote-system/internal/config"
 	"github.com/dew-77/mattermost-vote-system/internal/mattermost"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
 	"github.com/dew-77/mattermost-vote-system/internal/repository"
 )
 
 type App struct {
-	config     *config.Config
-	logger     *logrus.Logger
-	mmClient   *mattermost.Client
-	repository repository.PollRepository
+	config      *config.Config
+	logger      *logrus.Logger
+	mmClient    *mattermost.Client
+	repository  repository.PollRepository
+	permissions *PermissionService
+	eligibility *EligibilityService
+	groupIDs    *ttlCache[string]
+	reminders   []time.Duration
+	events      *EventBus
+	webhooks    *WebhookDispatcher
+	wsConnected atomic.Bool
 }
 
+var errWebSocketDisconnected = errors.New("websocket is not connected")
+
+const (
+	webSocketMinBackoff = time.Second
+	webSocketMaxBackoff = time.Minute
+)
+
 func NewApp(cfg *config.Config, logger *logrus.Logger, mmClient *mattermost.Client, repo repository.PollRepository) *App {
+	reminders, err := parseReminders(cfg.Bot.Reminders)
+	if err != nil {
+		logger.WithError(err).Warn("Invalid bot.reminders in config, reminders disabled by default")
+	}
+	
+	events := NewEventBus()
+	
+	var webhooks *WebhookDispatcher
+	if len(cfg.Webhooks.Endpoints) > 0 {
+		webhooks = NewWebhookDispatcher(cfg.Webhooks, repo, logger)
+		events.Subscribe(webhooks.Enqueue)
+	}
+	
 	return &App{
-		config:     cfg,
-		logger:     logger,
-		mmClient:   mmClient,
-		repository: repo,
+		config:      cfg,
+		logger:      logger,
+		mmClient:    mmClient,
+		repository:  repo,
+		permissions: NewPermissionService(mmClient, logger, cfg.Bot.Moderators),
+		eligibility: NewEligibilityService(mmClient),
+		groupIDs:    newTTLCache[string](eligibilityCacheTTL),
+		reminders:   reminders,
+		events:      events,
+		webhooks:    webhooks,
 	}
 }
 
@@ -34,15 +71,59 @@ func (a *App) Start() error {
 		return err
 	}
 	
-	wsClient.Listen()
+	if a.webhooks != nil {
+		a.webhooks.Start()
+	}
+	
+	go a.watchDeadlines()
+	go a.runSchedules()
+	go a.runRetention()
 	
 	a.logger.Info("Bot started and listening for events")
 	
 	for {
-		select {
-		case event := <-wsClient.EventChannel:
+		a.wsConnected.Store(true)
+		for event := range wsClient.EventChannel {
 			a.handleWebSocketEvent(event)
 		}
+		a.wsConnected.Store(false)
+		
+		if wsClient.ListenError != nil {
+			a.logger.WithError(wsClient.ListenError).Warn("WebSocket connection lost, reconnecting")
+		} else {
+			a.logger.Warn("WebSocket connection closed, reconnecting")
+		}
+		wsClient = a.reconnectWebSocket()
+	}
+}
+
+// CheckWebSocket сообщает, подключён ли бот к WebSocket Mattermost.
+func (a *App) CheckWebSocket() error {
+	if !a.wsConnected.Load() {
+		return errWebSocketDisconnected
+	}
+	return nil
+}
+
+// reconnectWebSocket переподключается к WebSocket Mattermost, увеличивая
+// паузу между попытками, пока подключение не удастся.
+func (a *App) reconnectWebSocket() *model.WebSocketClient {
+	backoff := webSocketMinBackoff
+	for {
+		time.Sleep(backoff)
+		
+		wsClient, err := a.mmClient.GetWebSocketClient()
+		if err == nil {
+			monitoring.WebSocketReconnects.Inc()
+			a.logger.Info("WebSocket reconnected")
+			return wsClient
+		}
+		
+		a.logger.WithError(err).Warn("Failed to reconnect WebSocket")
+		backoff *= 2
+		if backoff > webSocketMaxBackoff {
+			backoff = webSocketMaxBackoff
+		}
 	}
 }
 
@@ -95,33 +176,94 @@ func (a *App) handleCommand(userID, channelID, message string) {
 	}
 	
 	command := strings.ToLower(parts[0])
+	var err error
+	outcome := monitoring.OutcomeOK
 	
 	switch command {
 	case "create", "new", "poll":
-		a.handleCreatePoll(userID, channelID, parts[1:])
+		err = a.handleCreatePoll(userID, channelID, parts[1:])
+	case "propose":
+		err = a.handlePropose(userID, channelID, parts[1:])
 	case "vote":
-		a.handleVote(userID, channelID, parts[1:])
+		err = a.handleVote(userID, channelID, parts[1:])
+	case "unvote":
+		err = a.handleUnvote(userID, channelID, parts[1:])
+	case "edit":
+		err = a.handleEditPoll(userID, channelID, parts[1:])
+	case "weights":
+		err = a.handleWeights(userID, channelID, parts[1:])
 	case "results":
-		a.handleResults(channelID, parts[1:])
+		err = a.handleResults(channelID, parts[1:])
+	case "stats":
+		err = a.handleStats(channelID, parts[1:])
 	case "finish":
-		a.handleFinishPoll(userID, channelID, parts[1:])
+		err = a.handleFinishPoll(userID, channelID, parts[1:])
+	case "reopen":
+		err = a.handleReopenPoll(userID, channelID, parts[1:])
+	case "extend":
+		err = a.handleExtendPoll(userID, channelID, parts[1:])
+	case "decide":
+		err = a.handleDecide(userID, channelID, parts[1:])
+	case "export":
+		err = a.handleExport(userID, channelID, parts[1:])
+	case "schedule":
+		err = a.handleSchedule(userID, channelID, parts[1:])
+	case "schedules":
+		err = a.handleSchedules(channelID)
+	case "unschedule":
+		err = a.handleUnschedule(userID, channelID, parts[1:])
 	case "delete":
-		a.handleDeletePoll(userID, channelID, parts[1:])
+		err = a.handleDeletePoll(userID, channelID, parts[1:])
 	case "help":
 		a.replyHelp(channelID)
 	default:
+		command, outcome = "unknown", monitoring.OutcomeUnknown
 		a.replyHelp(channelID)
 	}
+	
+	if err != nil {
+		outcome = monitoring.OutcomeError
+	}
+	monitoring.CommandsTotal.WithLabelValues(command, outcome).Inc()
+}
+
+// errCommandFailed возвращают обработчики команд, ответившие пользователю
+// сообщением об ошибке; по нему команда учитывается в метриках как неудачная.
+var errCommandFailed = errors.New("command failed")
+
+// replyError отвечает сообщением об ошибке и возвращает errCommandFailed,
+// чтобы обработчик мог сразу вернуть его.
+func (a *App) replyError(channelID, message string) error {
+	a.mmClient.CreatePost(channelID, message)
+	return errCommandFailed
 }
 
 func (a *App) replyHelp(channelID string) {
 	helpText := `### Команды голосования:
-- **create "Заголовок" "Вариант 1" "Вариант 2" ...** - Создать новое голосование
+- **create "Заголовок" "Вариант 1" "Вариант 2" ... [--opens-at 15:00] [--for 1d] [--remind 1h 15m] [--remind-via channel|dm] [--anonymous] [--voters @user1 @user2] [--group devs] [--quorum 10|50%] [--threshold majority|2/3|unanimity] [--tie report|creator|earliest|random] [--type quadratic --credits 100] [--type stv --seats 3] [--type approval]** - Создать новое голосование (с --opens-at откроется для голосования в указанное время, с --for закроется автоматически, --remind и --remind-via задают напоминания до завершения, --anonymous скрывает, кто как проголосовал, --voters и --group ограничивают круг голосующих, --quorum и --threshold задают условия принятия решения, --tie — правило разрешения ничьей, --type quadratic — квадратичное голосование с бюджетом кредитов, --type stv — выборы на несколько мест единым передаваемым голосом, --type approval — голосование одобрением)
+- **propose "Текст" [--threshold majority|2/3|60%] [--quorum 10|50%] [--for 1d]** - Предложение с вариантами «за / против / воздержаться»
 - **vote [ID голосования] [номер варианта]** - Проголосовать за вариант
+- **vote [ID голосования] [номер:голоса] ...** - Распределить голоса в квадратичном голосовании, например vote abc123 1:3 4:2
+- **vote [ID голосования] [номер] [номер] ...** - Ранжированный бюллетень в выборах STV: номера кандидатов в порядке предпочтения, например vote abc123 3 1 2
+- **vote [ID голосования] [номер] [номер] ...** - Голосование одобрением: номера всех приемлемых вариантов, например vote abc123 1 2 5
+- **unvote [ID голосования]** - Отозвать свой голос
+- **weights [ID голосования] [@пользователь вес]** - Показать или назначить вес голоса (назначает только создатель)
 - **results [ID голосования]** - Показать результаты голосования
-- **finish [ID голосования]** - Завершить голосование (только для создателя)
-- **delete [ID голосования]** - Удалить голосование (только для создателя)
+- **stats [ID голосования]** - Статистика голосования: голоса по часам, изменения голоса, время до первого голоса и явка относительно участников канала
+- **stats channel** - Сводка по голосованиям канала: число голосований, средняя явка и самые активные участники (без анонимных голосований)
+- **export [ID голосования] csv|json [--anonymize]** - Выгрузить бюллетени и итоги файлом (с --anonymize вместо имён — обезличенные токены; для анонимных голосований — только итоги)
+- **edit [ID голосования] title "Заголовок"** - Изменить заголовок (создатель или администратор)
+- **edit [ID голосования] add "Вариант"** - Добавить вариант (создатель или администратор)
+- **edit [ID голосования] remove [номер варианта] [--force]** - Удалить вариант; если за него уже голосовали, нужен --force (создатель или администратор)
+- **finish [ID голосования]** - Завершить голосование (создатель или администратор)
+- **reopen [ID голосования] [--for 1d]** - Открыть завершённое голосование заново (создатель или администратор)
+- **extend [ID голосования] --for 1d** - Продлить срок голосования (создатель или администратор)
+- **decide [ID голосования] [номер варианта]** - Решающий голос создателя при ничьей (для голосований с --tie creator)
+- **delete [ID голосования]** - Удалить голосование (создатель или администратор)
+- **schedule "0 10 * * MON" create "Заголовок" "Вариант 1" "Вариант 2" ... [--opens-at 2h] [--for 4h]** - Повторяющееся голосование по расписанию в формате cron (минуты часы день месяц день_недели); без --for голосование закрывается к следующему запуску, --opens-at задаётся только задержкой от запуска
+- **schedules** - Показать повторяющиеся голосования канала
+- **unschedule [ID расписания]** - Удалить расписание (создатель или администратор)
 - **help** - Показать эту справку`
-
+	
 	a.mmClient.CreatePost(channelID, helpText)
 }
@@ -0,0 +1,144 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseCommandArgs разбирает аргументы команды с учётом кавычек.
+// Флаги вида --name забирают следующие за ними слова без кавычек в качестве
+// значений; строки в кавычках всегда считаются позиционными аргументами.
+func parseCommandArgs(args []string) ([]string, map[string][]string) {
+	var positional []string
+	flags := make(map[string][]string)
+	
+	currentFlag := ""
+	for _, token := range tokenize(strings.Join(args, " ")) {
+		switch {
+		case !token.quoted && strings.HasPrefix(token.text, "--"):
+			currentFlag = strings.ToLower(strings.TrimPrefix(token.text, "--"))
+			if _, ok := flags[currentFlag]; !ok {
+				flags[currentFlag] = nil
+			}
+		case !token.quoted && currentFlag != "":
+			flags[currentFlag] = append(flags[currentFlag], token.text)
+		default:
+			currentFlag = ""
+			positional = append(positional, token.text)
+		}
+	}
+	
+	return positional, flags
+}
+
+type argToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(s string) []argToken {
+	var tokens []argToken
+	var current strings.Builder
+	inQuotes := false
+	
+	flush := func(quoted bool) {
+		if current.Len() > 0 || quoted {
+			tokens = append(tokens, argToken{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+	}
+	
+	for _, char := range s {
+		switch {
+		case char == '"':
+			if inQuotes {
+				flush(true)
+			} else {
+				flush(false)
+			}
+			inQuotes = !inQuotes
+		case !inQuotes && (char == ' ' || char == '\t' || char == '\n'):
+			flush(false)
+		default:
+			current.WriteRune(char)
+		}
+	}
+	flush(inQuotes)
+	
+	return tokens
+}
+
+var longUnitPattern = regexp.MustCompile(`(\d+)([wd])`)
+
+// parseDuration расширяет time.ParseDuration суффиксами d (дни) и w (недели).
+func parseDuration(s string) (time.Duration, error) {
+	var total time.Duration
+	
+	rest := longUnitPattern.ReplaceAllStringFunc(strings.ToLower(s), func(m string) string {
+		n, _ := strconv.Atoi(m[:len(m)-1])
+		unit := 24 * time.Hour
+		if strings.HasSuffix(m, "w") {
+			unit *= 7
+		}
+		total += time.Duration(n) * unit
+		return ""
+	})
+	
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		total += d
+	}
+	
+	if total <= 0 {
+		return 0, fmt.Errorf("duration must be positive: %q", s)
+	}
+	
+	return total, nil
+}
+
+var opensAtLayouts = []string{
+	"02.01.2006 15:04",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	time.RFC3339,
+}
+
+// parseOpensAt разбирает время открытия: полную дату, время суток
+// (сегодня или завтра, если оно уже прошло) или задержку относительно now.
+func parseOpensAt(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	
+	for _, layout := range opensAtLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		opensAt := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !opensAt.After(now) {
+			opensAt = opensAt.AddDate(0, 0, 1)
+		}
+		return opensAt, nil
+	}
+	
+	delay, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid opening time %q", s)
+	}
+	return now.Add(delay), nil
+}
+
+func flagValue(flags map[string][]string, name string) (string, bool) {
+	values, ok := flags[name]
+	if !ok || len(values) == 0 {
+		return "", ok
+	}
+	return values[0], true
+}
\ No newline at end of file
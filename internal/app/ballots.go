@@ -0,0 +1,138 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// parseAllocation разбирает бюллетень квадратичного голосования вида "1:3 4:2",
+// где слева номер варианта, справа число голосов. Бюджет здесь проверяется
+// только для понятного сообщения; окончательно его проверяет репозиторий.
+func (a *App) parseAllocation(poll models.Poll, args []string, channelID string) (map[int]int, bool) {
+	allocation := make(map[int]int)
+	cost := 0
+	
+	for _, arg := range args {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Неверный формат `%s`. Используйте [номер:голоса], например `vote %s 1:3 2:1`.", arg, poll.ID))
+			return nil, false
+		}
+		
+		optionIdx, err := strconv.Atoi(parts[0])
+		if err != nil || optionIdx < 1 || optionIdx > len(poll.Options) {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Номер варианта должен быть от 1 до %d.", len(poll.Options)))
+			return nil, false
+		}
+		
+		votes, err := strconv.Atoi(parts[1])
+		if err != nil || votes < 1 {
+			a.replyError(channelID, "Ошибка: Число голосов должно быть положительным целым числом.")
+			return nil, false
+		}
+		
+		optionID := poll.Options[optionIdx-1].ID
+		if _, ok := allocation[optionID]; ok {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Вариант %d указан несколько раз.", optionIdx))
+			return nil, false
+		}
+		
+		allocation[optionID] = votes
+		cost += votes * votes
+	}
+	
+	if cost > poll.Credits {
+		a.replyError(channelID, fmt.Sprintf("Ошибка: Бюллетень стоит %d кредитов, а доступно только %d. Стоимость голосов за вариант — квадрат их числа.", cost, poll.Credits))
+		return nil, false
+	}
+	
+	return allocation, true
+}
+
+func formatAllocation(poll models.Poll, allocation map[int]int) string {
+	var parts []string
+	for i, option := range poll.Options {
+		if votes, ok := allocation[option.ID]; ok {
+			parts = append(parts, fmt.Sprintf("%d:%d", i+1, votes))
+		}
+	}
+	return "варианты " + strings.Join(parts, ", ")
+}
+
+// parseRanking разбирает ранжированный бюллетень вида "3 1 2": номера
+// кандидатов в порядке предпочтения. Ранжировать всех кандидатов не обязательно.
+func (a *App) parseRanking(poll models.Poll, args []string, channelID string) ([]int, bool) {
+	ranking := make([]int, 0, len(args))
+	
+	for _, arg := range args {
+		optionIdx, err := strconv.Atoi(arg)
+		if err != nil || optionIdx < 1 || optionIdx > len(poll.Options) {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Номер кандидата должен быть от 1 до %d. Перечислите номера в порядке предпочтения, например `vote %s 3 1 2`.", len(poll.Options), poll.ID))
+			return nil, false
+		}
+		
+		optionID := poll.Options[optionIdx-1].ID
+		for _, ranked := range ranking {
+			if ranked == optionID {
+				a.replyError(channelID, fmt.Sprintf("Ошибка: Кандидат %d указан несколько раз.", optionIdx))
+				return nil, false
+			}
+		}
+		
+		ranking = append(ranking, optionID)
+	}
+	
+	return ranking, true
+}
+
+func formatRanking(poll models.Poll, ranking []int) string {
+	parts := make([]string, 0, len(ranking))
+	for _, optionID := range ranking {
+		for i, option := range poll.Options {
+			if option.ID == optionID {
+				parts = append(parts, strconv.Itoa(i+1))
+			}
+		}
+	}
+	return "кандидатов в порядке " + strings.Join(parts, " > ")
+}
+
+// parseApprovals разбирает бюллетень голосования одобрением вида "1 2 5":
+// номера всех приемлемых вариантов. Порядок не важен.
+func (a *App) parseApprovals(poll models.Poll, args []string, channelID string) ([]int, bool) {
+	approved := make([]int, 0, len(args))
+	
+	for _, arg := range args {
+		optionIdx, err := strconv.Atoi(arg)
+		if err != nil || optionIdx < 1 || optionIdx > len(poll.Options) {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Номер варианта должен быть от 1 до %d. Перечислите все приемлемые варианты, например `vote %s 1 2 5`.", len(poll.Options), poll.ID))
+			return nil, false
+		}
+		
+		optionID := poll.Options[optionIdx-1].ID
+		if slices.Contains(approved, optionID) {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Вариант %d указан несколько раз.", optionIdx))
+			return nil, false
+		}
+		
+		approved = append(approved, optionID)
+	}
+	
+	sort.Ints(approved)
+	return approved, true
+}
+
+func formatApprovals(poll models.Poll, approved []int) string {
+	var parts []string
+	for i, option := range poll.Options {
+		if slices.Contains(approved, option.ID) {
+			parts = append(parts, strconv.Itoa(i+1))
+		}
+	}
+	return "варианты " + strings.Join(parts, ", ")
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/pkg/chart"
+)
+
+// postResults публикует сообщение с результатами и диаграммой. Если
+// диаграмму не удалось построить или загрузить, публикуется только текст.
+func (a *App) postResults(channelID, message string, results models.PollResults) {
+	image, err := chart.RenderBars(resultBars(results))
+	if err != nil {
+		a.logger.WithError(err).WithField("poll_id", results.Poll.ID).Warn("Failed to render results chart")
+		a.mmClient.CreatePost(channelID, message)
+		return
+	}
+	
+	filename := fmt.Sprintf("poll-%s-results.png", results.Poll.ID)
+	fileID, err := a.mmClient.UploadFile(channelID, filename, image)
+	if err != nil {
+		a.logger.WithError(err).WithField("poll_id", results.Poll.ID).Warn("Failed to upload results chart")
+		a.mmClient.CreatePost(channelID, message)
+		return
+	}
+	
+	if _, err := a.mmClient.CreatePostWithFiles(channelID, message, []string{fileID}); err != nil {
+		a.logger.WithError(err).WithField("poll_id", results.Poll.ID).Error("Failed to post results with chart")
+		a.mmClient.CreatePost(channelID, message)
+	}
+}
+
+// resultBars строит полосы по тем же числам, что и итог: по весам, если они есть.
+// Для STV показываются первые предпочтения.
+func resultBars(results models.PollResults) []chart.Bar {
+	tally := results.Tally()
+	if results.Poll.IsSTV() {
+		tally = results.Results
+	}
+	
+	bars := make([]chart.Bar, len(results.Poll.Options))
+	for i, option := range results.Poll.Options {
+		bars[i] = chart.Bar{
+			Label: fmt.Sprintf("%d.", i+1),
+			Value: tally[option.ID],
+		}
+	}
+	return bars
+}
\ No newline at end of file
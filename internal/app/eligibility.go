@@ -0,0 +1,179 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/mattermost"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+const eligibilityCacheTTL = 5 * time.Minute
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+type ttlCache[V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry[V]
+}
+
+func newTTLCache[V any](ttl time.Duration) *ttlCache[V] {
+	return &ttlCache[V]{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry[V]),
+	}
+}
+
+func (c *ttlCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache[V]) set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	c.entries[key] = cacheEntry[V]{value: value, expires: time.Now().Add(c.ttl)}
+}
+
+// EligibilityService проверяет, может ли пользователь голосовать:
+// он должен состоять в канале голосования и, если задан список, входить в него.
+type EligibilityService struct {
+	mmClient     *mattermost.Client
+	memberships  *ttlCache[bool]
+	groupMembers *ttlCache[map[string]bool]
+}
+
+func NewEligibilityService(mmClient *mattermost.Client) *EligibilityService {
+	return &EligibilityService{
+		mmClient:     mmClient,
+		memberships:  newTTLCache[bool](eligibilityCacheTTL),
+		groupMembers: newTTLCache[map[string]bool](eligibilityCacheTTL),
+	}
+}
+
+func (e *EligibilityService) Check(poll models.Poll, userID string) error {
+	isMember, err := e.isChannelMember(poll.ChannelID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return models.ErrNotChannelMember
+	}
+	
+	if !poll.HasVoterList() {
+		return nil
+	}
+	
+	for _, voterID := range poll.EligibleVoters {
+		if voterID == userID {
+			return nil
+		}
+	}
+	
+	for _, groupID := range poll.EligibleGroups {
+		members, err := e.groupMemberSet(groupID)
+		if err != nil {
+			return err
+		}
+		if members[userID] {
+			return nil
+		}
+	}
+	
+	return models.ErrNotEligible
+}
+
+// EligibleVoters возвращает участников канала голосования, имеющих право голоса,
+// не считая самого бота.
+func (e *EligibilityService) EligibleVoters(poll models.Poll) ([]string, error) {
+	memberIDs, err := e.mmClient.GetChannelMemberIDs(poll.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	
+	listed := make(map[string]bool, len(poll.EligibleVoters))
+	for _, voterID := range poll.EligibleVoters {
+		listed[voterID] = true
+	}
+	
+	var groups []map[string]bool
+	for _, groupID := range poll.EligibleGroups {
+		members, err := e.groupMemberSet(groupID)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, members)
+	}
+	
+	var eligible []string
+	for _, userID := range memberIDs {
+		if userID == e.mmClient.GetBotUserID() {
+			continue
+		}
+		
+		e.memberships.set(poll.ChannelID+":"+userID, true)
+		
+		if poll.HasVoterList() && !listed[userID] && !inAnyGroup(groups, userID) {
+			continue
+		}
+		eligible = append(eligible, userID)
+	}
+	
+	return eligible, nil
+}
+
+func inAnyGroup(groups []map[string]bool, userID string) bool {
+	for _, members := range groups {
+		if members[userID] {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *EligibilityService) isChannelMember(channelID, userID string) (bool, error) {
+	key := channelID + ":" + userID
+	if isMember, ok := e.memberships.get(key); ok {
+		return isMember, nil
+	}
+	
+	isMember, err := e.mmClient.IsChannelMember(channelID, userID)
+	if err != nil {
+		return false, err
+	}
+	
+	e.memberships.set(key, isMember)
+	return isMember, nil
+}
+
+func (e *EligibilityService) groupMemberSet(groupID string) (map[string]bool, error) {
+	if members, ok := e.groupMembers.get(groupID); ok {
+		return members, nil
+	}
+	
+	userIDs, err := e.mmClient.GetGroupMemberIDs(groupID)
+	if err != nil {
+		return nil, err
+	}
+	
+	members := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		members[userID] = true
+	}
+	
+	e.groupMembers.set(groupID, members)
+	return members, nil
+}
\ No newline at end of file
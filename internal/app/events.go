@@ -0,0 +1,61 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// EventBus рассылает события жизненного цикла голосований подписчикам.
+// Обработчики вызываются синхронно, поэтому не должны блокироваться:
+// долгую работу (например, отправку вебхуков) они выполняют в своих горутинах.
+type EventBus struct {
+	mu       sync.RWMutex
+	handlers []func(models.Event)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+func (b *EventBus) Subscribe(handler func(models.Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *EventBus) Publish(event models.Event) {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
+
+func (a *App) emit(eventType string, poll models.Poll, userID string) {
+	a.events.Publish(models.Event{Type: eventType, Poll: poll, UserID: userID})
+}
+
+func (a *App) emitVote(poll models.Poll, vote models.Vote) {
+	if poll.Anonymous {
+		a.events.Publish(models.Event{Type: models.EventVoteCast, Poll: poll})
+		return
+	}
+	a.events.Publish(models.Event{Type: models.EventVoteCast, Poll: poll, UserID: vote.UserID, Vote: &vote})
+}
+
+func (a *App) emitFinished(results models.PollResults, userID string) {
+	if results.Poll.Anonymous {
+		results.Voters = nil
+	}
+	a.events.Publish(models.Event{Type: models.EventPollFinished, Poll: results.Poll, UserID: userID, Results: &results})
+}
\ No newline at end of file
@@ -0,0 +1,284 @@
+package app
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// exportBallot — строка выгрузки: один выбранный вариант одного бюллетеня.
+type exportBallot struct {
+	Voter        string    `json:"voter"`
+	OptionID     int       `json:"option_id"`
+	OptionNumber int       `json:"option_number"`
+	Option       string    `json:"option"`
+	Votes        int       `json:"votes,omitempty"`
+	Rank         int       `json:"rank,omitempty"`
+	VotedAt      time.Time `json:"voted_at"`
+}
+
+type exportDocument struct {
+	ExportedAt time.Time          `json:"exported_at"`
+	Poll       models.Poll        `json:"poll"`
+	Results    models.PollResults `json:"results"`
+	Ballots    []exportBallot     `json:"ballots,omitempty"`
+}
+
+func (a *App) handleExport(userID, channelID string, args []string) error {
+	if len(args) < 2 {
+		return a.replyError(channelID, "Ошибка: Используйте: export [ID голосования] csv|json [--anonymize]")
+	}
+	
+	pollID := args[0]
+	format := strings.ToLower(args[1])
+	anonymize := hasFlag(args[2:], "--anonymize")
+	
+	if format != "csv" && format != "json" {
+		return a.replyError(channelID, "Ошибка: Формат выгрузки должен быть csv или json.")
+	}
+	
+	results, err := a.repository.GetPollResults(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	poll := results.Poll
+	
+	// Выгрузка из другого канала раскрыла бы голоса его участникам
+	if poll.ChannelID != channelID && !a.permissions.CanManage(userID, poll) {
+		return a.replyError(channelID, "Ошибка: Выгрузить голосование можно только в его канале, если вы не его создатель или администратор.")
+	}
+	
+	var (
+		votes []models.Vote
+		names map[string]string
+	)
+	if !poll.Anonymous {
+		votes, err = a.repository.GetVotes(pollID)
+		if err != nil {
+			a.logger.WithError(err).Error("Failed to get votes for export")
+			return a.replyError(channelID, "Ошибка при получении голосов.")
+		}
+		names, err = a.voterNames(votes, anonymize)
+		if err != nil {
+			a.logger.WithError(err).Error("Failed to anonymize voters for export")
+			return a.replyError(channelID, "Ошибка при обезличивании голосующих.")
+		}
+	}
+	
+	doc := newExportDocument(results, votes, names, anonymize)
+	
+	var data []byte
+	if format == "json" {
+		data, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		data, err = exportCSV(doc)
+	}
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to build export file")
+		return a.replyError(channelID, "Ошибка при формировании файла выгрузки.")
+	}
+	
+	filename := fmt.Sprintf("poll-%s-%s.%s", poll.ID, doc.ExportedAt.Format("20060102-150405"), format)
+	
+	fileID, err := a.mmClient.UploadFile(channelID, filename, data)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to upload export file")
+		return a.replyError(channelID, "Ошибка при загрузке файла выгрузки.")
+	}
+	
+	message := fmt.Sprintf("Выгрузка голосования `%s` (%s).", poll.ID, strings.ToUpper(format))
+	if poll.Anonymous {
+		message += " Голосование анонимное, поэтому выгружены только итоги."
+	}
+	
+	if _, err := a.mmClient.CreatePostWithFiles(channelID, message, []string{fileID}); err != nil {
+		a.logger.WithError(err).Error("Failed to post export file")
+		return a.replyError(channelID, "Ошибка при публикации файла выгрузки.")
+	}
+	
+	a.recordAudit(poll.ID, userID, "export", format)
+	return nil
+}
+
+// newExportDocument собирает выгрузку. В анонимных голосованиях выгружаются
+// только итоги, без бюллетеней и состава участников.
+func newExportDocument(results models.PollResults, votes []models.Vote, names map[string]string, anonymize bool) exportDocument {
+	doc := exportDocument{
+		ExportedAt: time.Now(),
+		Poll:       results.Poll,
+		Results:    results,
+	}
+	
+	if doc.Poll.Anonymous {
+		doc.Poll.EligibleVoters, doc.Poll.EligibleGroups = nil, nil
+		doc.Results.Poll = doc.Poll
+		doc.Results.Voters = nil
+		return doc
+	}
+	
+	doc.Ballots = exportBallots(doc.Poll, votes, names)
+	if anonymize {
+		doc.Results.Voters = nil
+	}
+	return doc
+}
+
+// voterNames сопоставляет голосующим их имена пользователей либо, при
+// anonymize, обезличенные токены.
+func (a *App) voterNames(votes []models.Vote, anonymize bool) (map[string]string, error) {
+	if anonymize {
+		return anonymizedNames(votes)
+	}
+	
+	names := make(map[string]string, len(votes))
+	for _, vote := range votes {
+		if _, ok := names[vote.UserID]; ok {
+			continue
+		}
+		
+		names[vote.UserID] = vote.UserID
+		if user, err := a.mmClient.GetUser(vote.UserID); err == nil {
+			names[vote.UserID] = user.Username
+		}
+	}
+	
+	return names, nil
+}
+
+// anonymizedNames строит токены как HMAC идентификатора со случайным ключом
+// выгрузки: в одной выгрузке токен голосующего один и тот же, а токены разных
+// выгрузок нельзя связать между собой.
+func anonymizedNames(votes []models.Vote) (map[string]string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate anonymization key: %w", err)
+	}
+	
+	names := make(map[string]string, len(votes))
+	for _, vote := range votes {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(vote.UserID))
+		names[vote.UserID] = "voter-" + hex.EncodeToString(mac.Sum(nil))[:12]
+	}
+	return names, nil
+}
+
+func exportBallots(poll models.Poll, votes []models.Vote, names map[string]string) []exportBallot {
+	numbers := make(map[int]int, len(poll.Options))
+	for i, option := range poll.Options {
+		numbers[option.ID] = i + 1
+	}
+	
+	var ballots []exportBallot
+	add := func(vote models.Vote, optionID, count, rank int) {
+		option, ok := poll.OptionByID(optionID)
+		if !ok {
+			return
+		}
+		ballots = append(ballots, exportBallot{
+			Voter:        names[vote.UserID],
+			OptionID:     optionID,
+			OptionNumber: numbers[optionID],
+			Option:       option.Text,
+			Votes:        count,
+			Rank:         rank,
+			VotedAt:      vote.VotedAt,
+		})
+	}
+	
+	for _, vote := range votes {
+		switch {
+		case poll.IsQuadratic():
+			for _, option := range poll.Options {
+				if count, ok := vote.Allocation[option.ID]; ok {
+					add(vote, option.ID, count, 0)
+				}
+			}
+		case poll.IsSTV():
+			for i, optionID := range vote.Ranking {
+				add(vote, optionID, 0, i+1)
+			}
+		case poll.IsApproval():
+			for _, optionID := range vote.Approved {
+				add(vote, optionID, 1, 0)
+			}
+		default:
+			add(vote, vote.OptionID, 1, 0)
+		}
+	}
+	
+	return ballots
+}
+
+// exportCSV выгружает бюллетени и после пустой строки — итоги по вариантам.
+// Для анонимных голосований выгружаются только итоги.
+func exportCSV(doc exportDocument) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	
+	if doc.Poll.Anonymous {
+		writeAggregates(writer, doc.Results)
+	} else {
+		writer.Write([]string{"voter", "option_number", "option", "votes", "rank", "voted_at"})
+		for _, ballot := range doc.Ballots {
+			writer.Write([]string{
+				ballot.Voter,
+				strconv.Itoa(ballot.OptionNumber),
+				ballot.Option,
+				strconv.Itoa(ballot.Votes),
+				strconv.Itoa(ballot.Rank),
+				ballot.VotedAt.Format(time.RFC3339),
+			})
+		}
+		writer.Write(nil)
+		writeAggregates(writer, doc.Results)
+	}
+	
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+func writeAggregates(writer *csv.Writer, results models.PollResults) {
+	poll := results.Poll
+	tally := results.Tally()
+	
+	winners := make(map[int]bool)
+	if results.Winner != nil && results.Winner.Resolved {
+		winners[results.Winner.OptionID] = true
+	}
+	if results.STV != nil {
+		for _, optionID := range results.STV.Elected {
+			winners[optionID] = true
+		}
+	}
+	
+	writer.Write([]string{"option_number", "option", "votes", "tally", "winner"})
+	for i, option := range poll.Options {
+		winner := ""
+		if winners[option.ID] {
+			winner = "yes"
+		}
+		writer.Write([]string{
+			strconv.Itoa(i + 1),
+			option.Text,
+			strconv.Itoa(results.Results[option.ID]),
+			strconv.Itoa(tally[option.ID]),
+			winner,
+		})
+	}
+	writer.Write([]string{"", "total_voters", strconv.Itoa(results.TotalVotes), strconv.Itoa(results.DecisiveTotal()), results.Outcome})
+	if results.Winner != nil && results.Winner.Tie {
+		writer.Write([]string{"", "tie_break", results.Winner.TieBreak, "", strconv.FormatBool(results.Winner.Resolved)})
+	}
+}
\ No newline at end of file
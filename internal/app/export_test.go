@@ -0,0 +1,300 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+var exportTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func exportPoll(kind string) models.Poll {
+	return models.Poll{
+		ID:      "p1",
+		Title:   "Обед",
+		Kind:    kind,
+		Options: models.NewOptions([]string{"Пицца", "Суши", "Суп"}),
+	}
+}
+
+func TestExportBallots(t *testing.T) {
+	names := map[string]string{"u1": "alice", "u2": "bob"}
+	
+	tests := []struct {
+		name    string
+		kind    string
+		votes   []models.Vote
+		ballots []exportBallot
+	}{
+		{
+			name: "standard",
+			kind: models.PollKindStandard,
+			votes: []models.Vote{
+				{UserID: "u1", OptionID: 1, VotedAt: exportTime},
+				// Вариант удалён из голосования
+				{UserID: "u2", OptionID: 7, VotedAt: exportTime},
+			},
+			ballots: []exportBallot{
+				{Voter: "alice", OptionID: 1, OptionNumber: 2, Option: "Суши", Votes: 1, VotedAt: exportTime},
+			},
+		},
+		{
+			name: "quadratic",
+			kind: models.PollKindQuadratic,
+			votes: []models.Vote{
+				{UserID: "u1", OptionID: models.NoOption, Allocation: map[int]int{2: 1, 0: 3}, VotedAt: exportTime},
+			},
+			ballots: []exportBallot{
+				{Voter: "alice", OptionID: 0, OptionNumber: 1, Option: "Пицца", Votes: 3, VotedAt: exportTime},
+				{Voter: "alice", OptionID: 2, OptionNumber: 3, Option: "Суп", Votes: 1, VotedAt: exportTime},
+			},
+		},
+		{
+			name: "stv",
+			kind: models.PollKindSTV,
+			votes: []models.Vote{
+				{UserID: "u2", OptionID: models.NoOption, Ranking: []int{2, 0}, VotedAt: exportTime},
+			},
+			ballots: []exportBallot{
+				{Voter: "bob", OptionID: 2, OptionNumber: 3, Option: "Суп", Rank: 1, VotedAt: exportTime},
+				{Voter: "bob", OptionID: 0, OptionNumber: 1, Option: "Пицца", Rank: 2, VotedAt: exportTime},
+			},
+		},
+		{
+			name: "approval",
+			kind: models.PollKindApproval,
+			votes: []models.Vote{
+				{UserID: "u1", OptionID: models.NoOption, Approved: []int{0, 1}, VotedAt: exportTime},
+			},
+			ballots: []exportBallot{
+				{Voter: "alice", OptionID: 0, OptionNumber: 1, Option: "Пицца", Votes: 1, VotedAt: exportTime},
+				{Voter: "alice", OptionID: 1, OptionNumber: 2, Option: "Суши", Votes: 1, VotedAt: exportTime},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exportBallots(exportPoll(tt.kind), tt.votes, names)
+			if !reflect.DeepEqual(got, tt.ballots) {
+				t.Errorf("exportBallots() = %+v, want %+v", got, tt.ballots)
+			}
+		})
+	}
+}
+
+func readCSV(t *testing.T, data []byte) [][]string {
+	t.Helper()
+	
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	return rows
+}
+
+func TestExportCSV(t *testing.T) {
+	poll := exportPoll(models.PollKindSTV)
+	poll.Seats = 2
+	results := models.PollResults{
+		Poll:       poll,
+		Results:    map[int]int{0: 1, 1: 0, 2: 1},
+		Voters:     map[string]int{"u1": 2, "u2": 0},
+		TotalVotes: 2,
+		STV:        &models.STVResult{Seats: 2, Elected: []int{2, 0}},
+		Outcome:    models.OutcomePassed,
+	}
+	votes := []models.Vote{
+		{UserID: "u1", OptionID: models.NoOption, Ranking: []int{2, 1}, VotedAt: exportTime},
+		{UserID: "u2", OptionID: models.NoOption, Ranking: []int{0}, VotedAt: exportTime},
+	}
+	
+	doc := newExportDocument(results, votes, map[string]string{"u1": "alice", "u2": "bob"}, false)
+	data, err := exportCSV(doc)
+	if err != nil {
+		t.Fatalf("exportCSV() error = %v", err)
+	}
+	
+	want := [][]string{
+		{"voter", "option_number", "option", "votes", "rank", "voted_at"},
+		{"alice", "3", "Суп", "0", "1", "2024-03-01T12:00:00Z"},
+		{"alice", "2", "Суши", "0", "2", "2024-03-01T12:00:00Z"},
+		{"bob", "1", "Пицца", "0", "1", "2024-03-01T12:00:00Z"},
+		{"option_number", "option", "votes", "tally", "winner"},
+		{"1", "Пицца", "1", "1", "yes"},
+		{"2", "Суши", "0", "0", ""},
+		{"3", "Суп", "1", "1", "yes"},
+		{"", "total_voters", "2", "2", models.OutcomePassed},
+	}
+	// Пустая строка между бюллетенями и итогами пропускается csv.Reader
+	if got := readCSV(t, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("exportCSV() rows = %q, want %q", got, want)
+	}
+}
+
+func TestWriteAggregates(t *testing.T) {
+	tests := []struct {
+		name    string
+		results models.PollResults
+		rows    [][]string
+	}{
+		{
+			// Во взвешенном голосовании tally — сумма весов
+			name: "weighted winner",
+			results: models.PollResults{
+				Poll:            exportPoll(models.PollKindStandard),
+				Results:         map[int]int{0: 2, 1: 1, 2: 0},
+				Weighted:        true,
+				WeightedResults: map[int]int{0: 2, 1: 5, 2: 0},
+				TotalWeight:     7,
+				TotalVotes:      3,
+				Winner:          &models.Winner{OptionID: 1, Votes: 5, Resolved: true},
+				Outcome:         models.OutcomePassed,
+			},
+			rows: [][]string{
+				{"option_number", "option", "votes", "tally", "winner"},
+				{"1", "Пицца", "2", "2", ""},
+				{"2", "Суши", "1", "5", "yes"},
+				{"3", "Суп", "0", "0", ""},
+				{"", "total_voters", "3", "7", models.OutcomePassed},
+			},
+		},
+		{
+			name: "unresolved tie",
+			results: models.PollResults{
+				Poll:       exportPoll(models.PollKindStandard),
+				Results:    map[int]int{0: 1, 1: 1, 2: 0},
+				TotalVotes: 2,
+				Winner:     &models.Winner{OptionID: -1, Votes: 1, Tie: true, TiedOptionIDs: []int{0, 1}, TieBreak: models.TieBreakReport},
+				Outcome:    models.OutcomeFailed,
+			},
+			rows: [][]string{
+				{"option_number", "option", "votes", "tally", "winner"},
+				{"1", "Пицца", "1", "1", ""},
+				{"2", "Суши", "1", "1", ""},
+				{"3", "Суп", "0", "0", ""},
+				{"", "total_voters", "2", "2", models.OutcomeFailed},
+				{"", "tie_break", models.TieBreakReport, "", "false"},
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			writer := csv.NewWriter(&buf)
+			writeAggregates(writer, tt.results)
+			writer.Flush()
+			
+			if got := readCSV(t, []byte(buf.String())); !reflect.DeepEqual(got, tt.rows) {
+				t.Errorf("writeAggregates() rows = %q, want %q", got, tt.rows)
+			}
+		})
+	}
+}
+
+// Анонимная выгрузка содержит только итоги: ни бюллетеней, ни того, кто
+// голосовал или мог голосовать.
+func TestExportAnonymousPoll(t *testing.T) {
+	poll := exportPoll(models.PollKindStandard)
+	poll.Anonymous = true
+	poll.EligibleVoters = []string{"u1", "u2"}
+	poll.EligibleGroups = []string{"devs"}
+	results := models.PollResults{
+		Poll:       poll,
+		Results:    map[int]int{0: 1, 1: 1, 2: 0},
+		Voters:     map[string]int{"u1": 0, "u2": 1},
+		TotalVotes: 2,
+	}
+	votes := []models.Vote{
+		{UserID: "u1", OptionID: 0, VotedAt: exportTime},
+		{UserID: "u2", OptionID: 1, VotedAt: exportTime},
+	}
+	
+	doc := newExportDocument(results, votes, map[string]string{"u1": "alice", "u2": "bob"}, false)
+	
+	if doc.Ballots != nil || doc.Results.Voters != nil {
+		t.Errorf("ballots = %v, voters = %v, want none", doc.Ballots, doc.Results.Voters)
+	}
+	for _, p := range []models.Poll{doc.Poll, doc.Results.Poll} {
+		if p.EligibleVoters != nil || p.EligibleGroups != nil {
+			t.Errorf("eligible voters = %v, groups = %v, want none", p.EligibleVoters, p.EligibleGroups)
+		}
+	}
+	
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csvData, err := exportCSV(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, output := range []string{string(data), string(csvData)} {
+		for _, leak := range []string{"u1", "u2", "alice", "bob", "devs"} {
+			if strings.Contains(output, leak) {
+				t.Errorf("export contains %q:\n%s", leak, output)
+			}
+		}
+	}
+	
+	if rows := readCSV(t, csvData); !slices.Equal(rows[0], []string{"option_number", "option", "votes", "tally", "winner"}) {
+		t.Errorf("first CSV row = %q, want aggregates header", rows[0])
+	}
+}
+
+// В одной выгрузке у голосующего один токен на все строки, токены разных
+// голосующих различаются, а разные выгрузки используют разные ключи.
+func TestAnonymizedNames(t *testing.T) {
+	poll := exportPoll(models.PollKindApproval)
+	votes := []models.Vote{
+		{UserID: "u1", OptionID: models.NoOption, Approved: []int{0, 1, 2}, VotedAt: exportTime},
+		{UserID: "u2", OptionID: models.NoOption, Approved: []int{1}, VotedAt: exportTime},
+	}
+	results := models.PollResults{Poll: poll, Voters: map[string]int{"u1": models.NoOption, "u2": models.NoOption}}
+	
+	names, err := anonymizedNames(votes)
+	if err != nil {
+		t.Fatalf("anonymizedNames() error = %v", err)
+	}
+	doc := newExportDocument(results, votes, names, true)
+	
+	if doc.Results.Voters != nil {
+		t.Errorf("voters = %v, want none", doc.Results.Voters)
+	}
+	if len(doc.Ballots) != 4 {
+		t.Fatalf("ballots = %d, want 4", len(doc.Ballots))
+	}
+	for _, ballot := range doc.Ballots[:3] {
+		if ballot.Voter != names["u1"] {
+			t.Errorf("voter = %q, want %q on every ballot row of u1", ballot.Voter, names["u1"])
+		}
+	}
+	if doc.Ballots[3].Voter != names["u2"] {
+		t.Errorf("voter = %q, want %q", doc.Ballots[3].Voter, names["u2"])
+	}
+	if names["u1"] == names["u2"] {
+		t.Errorf("voters share token %q", names["u1"])
+	}
+	for userID, token := range names {
+		if !strings.HasPrefix(token, "voter-") || strings.Contains(token, userID) {
+			t.Errorf("token for %s = %q", userID, token)
+		}
+	}
+	
+	again, err := anonymizedNames(votes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again["u1"] == names["u1"] {
+		t.Errorf("token %q repeats across exports", names["u1"])
+	}
+}
\ No newline at end of file
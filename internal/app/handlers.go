@@ -1,27 +1,42 @@
 package app
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mattermost/mattermost-server/v6/model"
 	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
 )
 
-func (a *App) handleCreatePoll(userID, channelID string, args []string) {
+func (a *App) handleCreatePoll(userID, channelID string, args []string) error {
+	if _, ok := a.createPoll(userID, channelID, args); !ok {
+		return errCommandFailed
+	}
+	return nil
+}
+
+// createPoll создаёт и публикует голосование по аргументам команды create.
+// Используется и командой, и планировщиком повторяющихся голосований.
+func (a *App) createPoll(userID, channelID string, args []string) (models.Poll, bool) {
 	if len(args) < 3 {
-		a.mmClient.CreatePost(channelID, "Ошибка: Недостаточно аргументов. Используйте: create \"Заголовок\" \"Вариант 1\" \"Вариант 2\" ...")
-		return
+		a.replyError(channelID, "Ошибка: Недостаточно аргументов. Используйте: create \"Заголовок\" \"Вариант 1\" \"Вариант 2\" ...")
+		return models.Poll{}, false
 	}
 	
-	fullText := strings.Join(args, " ")
-	parts := splitQuoted(fullText)
+	parts, flags := parseCommandArgs(args)
 	
 	if len(parts) < 3 {
-		a.mmClient.CreatePost(channelID, "Ошибка: Необходимо указать заголовок и минимум 2 варианта ответа в кавычках.")
-		return
+		a.replyError(channelID, "Ошибка: Необходимо указать заголовок и минимум 2 варианта ответа в кавычках.")
+		return models.Poll{}, false
 	}
 	
 	title := parts[0]
@@ -32,20 +47,236 @@ func (a *App) handleCreatePoll(userID, channelID string, args []string) {
 	poll := models.Poll{
 		ID:         pollID,
 		Title:      title,
-		Options:    options,
+		Options:    models.NewOptions(options),
+		CreatorID:  userID,
+		ChannelID:  channelID,
+		CreatedAt:  time.Now(),
+		Status:     models.PollStatusDraft,
+	}
+	
+	if !a.applyCreateFlags(&poll, flags, channelID) {
+		return models.Poll{}, false
+	}
+	
+	return a.publishPoll(poll, channelID)
+}
+
+func (a *App) handlePropose(userID, channelID string, args []string) error {
+	parts, flags := parseCommandArgs(args)
+	
+	if len(parts) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите текст предложения в кавычках. Используйте: propose \"Текст\" [--threshold 2/3|60%] [--quorum 50%]")
+	}
+	
+	poll := models.Poll{
+		ID:         uuid.New().String()[:8],
+		Title:      parts[0],
+		Options:    models.NewProposalOptions(),
 		CreatorID:  userID,
 		ChannelID:  channelID,
 		CreatedAt:  time.Now(),
-		IsFinished: false,
+		Status:     models.PollStatusDraft,
+		Kind:       models.PollKindProposal,
+	}
+	
+	if !a.applyCreateFlags(&poll, flags, channelID) {
+		return errCommandFailed
+	}
+	
+	if _, ok := a.publishPoll(poll, channelID); !ok {
+		return errCommandFailed
+	}
+	return nil
+}
+
+// applyCreateFlags применяет общие флаги создания голосования. Возвращает false,
+// если флаг некорректен; сообщение об ошибке уже отправлено в канал.
+func (a *App) applyCreateFlags(poll *models.Poll, flags map[string][]string, channelID string) bool {
+	if values, ok := flags["opens-at"]; ok {
+		opensAt, err := parseOpensAt(strings.Join(values, " "), poll.CreatedAt)
+		if err != nil || !opensAt.After(poll.CreatedAt) {
+			a.replyError(channelID, "Ошибка: Некорректное время открытия для --opens-at. Укажите время в будущем: 15:00, 25.12.2025 10:00 или через сколько открыть, например 2h.")
+			return false
+		}
+		poll.OpensAt = opensAt
+	}
+	
+	if value, ok := flagValue(flags, "for"); ok {
+		duration, err := parseDuration(value)
+		if err != nil {
+			a.replyError(channelID, "Ошибка: Некорректная длительность для --for. Примеры: 30m, 4h, 1d.")
+			return false
+		}
+		// Срок отсчитывается от открытия, если оно отложено
+		start := poll.CreatedAt
+		if poll.OpensAt.After(start) {
+			start = poll.OpensAt
+		}
+		poll.ClosesAt = start.Add(duration)
+	}
+	
+	if usernames := flags["voters"]; len(usernames) > 0 {
+		voterIDs, missing, err := a.resolveUsernames(usernames)
+		if err != nil {
+			a.logger.WithError(err).Error("Failed to resolve voters")
+			a.replyError(channelID, "Ошибка при получении списка участников.")
+			return false
+		}
+		if len(missing) > 0 {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Пользователи не найдены: %s", strings.Join(missing, ", ")))
+			return false
+		}
+		poll.EligibleVoters = voterIDs
+	}
+	
+	groupNames := append(flags["group"], flags["groups"]...)
+	for _, name := range groupNames {
+		group, err := a.mmClient.GetGroupByName(strings.TrimPrefix(name, "@"))
+		if err != nil {
+			a.logger.WithError(err).WithField("group", name).Warn("Failed to resolve group")
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Группа `%s` не найдена.", name))
+			return false
+		}
+		poll.EligibleGroups = append(poll.EligibleGroups, group.Id)
+	}
+	
+	if values, ok := flags["remind"]; ok {
+		reminders, err := parseReminders(values)
+		if err != nil || len(reminders) == 0 {
+			a.replyError(channelID, "Ошибка: Укажите, за сколько до завершения напомнить, например --remind 1h 15m.")
+			return false
+		}
+		poll.Reminders = reminders
+	}
+	
+	if value, ok := flagValue(flags, "remind-via"); ok {
+		switch strings.ToLower(value) {
+		case models.ReminderModeChannel, models.ReminderModeDM:
+			poll.ReminderMode = strings.ToLower(value)
+		default:
+			a.replyError(channelID, "Ошибка: Некорректный способ напоминания. Допустимые значения: channel, dm.")
+			return false
+		}
+	}
+	
+	if _, ok := flags["anonymous"]; ok {
+		poll.Anonymous = true
+	}
+	
+	if value, ok := flagValue(flags, "quorum"); ok {
+		quorum, err := models.ParseQuorum(value)
+		if err != nil {
+			a.replyError(channelID, "Ошибка: Некорректный кворум. Укажите число голосов (--quorum 10) или процент (--quorum 50%).")
+			return false
+		}
+		poll.Quorum = quorum
+	}
+	
+	if value, ok := flagValue(flags, "threshold"); ok {
+		threshold, err := models.ParseThreshold(value)
+		if err != nil {
+			a.replyError(channelID, "Ошибка: Некорректный порог. Допустимые значения: majority, 2/3, unanimity или процент, например 60%.")
+			return false
+		}
+		poll.Threshold = threshold
+	}
+	
+	if value, ok := flagValue(flags, "type"); ok && poll.Kind == models.PollKindStandard {
+		switch strings.ToLower(value) {
+		case "", "single", "standard":
+		case models.PollKindQuadratic:
+			poll.Kind = models.PollKindQuadratic
+			poll.Credits = a.config.Bot.QuadraticCredits
+		case models.PollKindSTV:
+			poll.Kind = models.PollKindSTV
+			poll.Seats = 1
+		case models.PollKindApproval:
+			poll.Kind = models.PollKindApproval
+		default:
+			a.replyError(channelID, "Ошибка: Неизвестный тип голосования. Допустимые значения: single, quadratic, stv, approval.")
+			return false
+		}
+	}
+	
+	if value, ok := flagValue(flags, "credits"); ok {
+		credits, err := strconv.Atoi(value)
+		if err != nil || credits <= 0 || !poll.IsQuadratic() {
+			a.replyError(channelID, "Ошибка: --credits задаёт положительный бюджет кредитов и используется только с --type quadratic.")
+			return false
+		}
+		poll.Credits = credits
+	}
+	
+	if value, ok := flagValue(flags, "seats"); ok {
+		seats, err := strconv.Atoi(value)
+		if err != nil || seats <= 0 || !poll.IsSTV() {
+			a.replyError(channelID, "Ошибка: --seats задаёт положительное число мест и используется только с --type stv.")
+			return false
+		}
+		if seats >= len(poll.Options) {
+			a.replyError(channelID, fmt.Sprintf("Ошибка: Мест (%d) должно быть меньше, чем кандидатов (%d).", seats, len(poll.Options)))
+			return false
+		}
+		poll.Seats = seats
+	}
+	
+	if value, ok := flagValue(flags, "tie"); ok {
+		tieBreak, err := models.ParseTieBreak(value)
+		if err != nil {
+			a.replyError(channelID, "Ошибка: Некорректное правило ничьей. Допустимые значения: report, creator, earliest, random.")
+			return false
+		}
+		poll.TieBreak = tieBreak
+		if tieBreak == models.TieBreakRandom {
+			poll.TieSeed = rand.Int63()
+		}
+	}
+	
+	if poll.IsBinding() {
+		if poll.Threshold == "" {
+			poll.Threshold = models.ThresholdMajority
+		}
+		a.refreshElectorate(poll)
+	}
+	
+	return true
+}
+
+func (a *App) publishPoll(poll models.Poll, channelID string) (models.Poll, bool) {
+	poll, err := a.PublishPoll(poll)
+	if errors.Is(err, errPollNotSaved) {
+		a.replyError(channelID, "Ошибка при сохранении голосования.")
+		return models.Poll{}, false
+	}
+	if err != nil {
+		a.replyError(channelID, "Ошибка при создании голосования.")
+		return models.Poll{}, false
+	}
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Голосование создано! ID: `%s`", poll.ID))
+	return poll, true
+}
+
+var errPollNotSaved = errors.New("poll not saved")
+
+// PublishPoll публикует сообщение голосования в его канале и сохраняет
+// голосование. Используется командами чата и HTTP API.
+func (a *App) PublishPoll(poll models.Poll) (models.Poll, error) {
+	poll.Status = models.PollStatusOpen
+	if poll.OpensAt.After(time.Now()) {
+		poll.Status = models.PollStatusScheduled
+	}
+	
+	if poll.IsBinding() && poll.Electorate == 0 {
+		a.refreshElectorate(&poll)
 	}
 	
 	message := formatPollMessage(poll, nil)
 	
-	post, err := a.mmClient.CreatePost(channelID, message)
+	post, err := a.mmClient.CreatePost(poll.ChannelID, message)
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to create poll post")
-		a.mmClient.CreatePost(channelID, "Ошибка при создании голосования.")
-		return
+		return models.Poll{}, err
 	}
 	
 	poll.PostID = post.Id
@@ -53,169 +284,1011 @@ func (a *App) handleCreatePoll(userID, channelID string, args []string) {
 	err = a.repository.CreatePoll(poll)
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to save poll to database")
-		a.mmClient.CreatePost(channelID, "Ошибка при сохранении голосования.")
-		return
+		return models.Poll{}, fmt.Errorf("%w: %v", errPollNotSaved, err)
 	}
 	
-	a.mmClient.CreatePost(channelID, fmt.Sprintf("Голосование создано! ID: `%s`", pollID))
+	monitoring.PollsCreated.WithLabelValues(monitoring.PollKind(poll.Kind)).Inc()
+	a.emit(models.EventPollCreated, poll, poll.CreatorID)
+	
+	return poll, nil
 }
 
-func (a *App) handleVote(userID, channelID string, args []string) {
-	if len(args) < 2 {
-		a.mmClient.CreatePost(channelID, "Ошибка: Недостаточно аргументов. Используйте: vote [ID голосования] [номер варианта]")
-		return
+// CreatePoll публикует голосование, созданное через HTTP API. Автор
+// должен состоять в канале, в котором создаётся голосование. Число
+// участников пересчитывается всегда, а не берётся из запроса.
+func (a *App) CreatePoll(poll models.Poll) (models.Poll, error) {
+	isMember, err := a.eligibility.isChannelMember(poll.ChannelID, poll.CreatorID)
+	if err != nil {
+		return models.Poll{}, fmt.Errorf("failed to check channel membership: %w", err)
+	}
+	if !isMember {
+		return models.Poll{}, models.ErrNotChannelMember
 	}
 	
-	pollID := args[0]
+	// PublishPoll пересчитывает число участников, если оно не задано
+	poll.Electorate = 0
+	return a.PublishPoll(poll)
+}
+
+// CastVote принимает голос, поданный через HTTP API, с теми же проверками
+// права голоса и весами групп, что и команда vote.
+func (a *App) CastVote(poll models.Poll, vote models.Vote) (models.Vote, error) {
+	return a.castVote(poll, vote, "api")
+}
+
+var errEligibilityCheck = errors.New("failed to check voter eligibility")
+
+func (a *App) castVote(poll models.Poll, vote models.Vote, source string) (models.Vote, error) {
+	err := a.eligibility.Check(poll, vote.UserID)
+	switch {
+	case errors.Is(err, models.ErrNotChannelMember), errors.Is(err, models.ErrNotEligible):
+		return vote, err
+	case err != nil:
+		return vote, fmt.Errorf("%w: %v", errEligibilityCheck, err)
+	}
 	
-	optionIdx, err := strconv.Atoi(args[1])
+	vote.Weight = a.groupWeight(vote.UserID)
+	
+	if poll.IsQuadratic() {
+		err = a.repository.AddQuadraticVote(vote)
+	} else {
+		err = a.repository.AddVote(vote)
+	}
 	if err != nil {
-		a.mmClient.CreatePost(channelID, "Ошибка: Номер варианта должен быть числом.")
-		return
+		return vote, err
+	}
+	
+	a.recordAudit(poll.ID, vote.UserID, "vote", "")
+	monitoring.VotesCast.WithLabelValues(monitoring.PollKind(poll.Kind), source).Inc()
+	a.refreshPollPost(poll)
+	a.emitVote(poll, vote)
+	
+	return vote, nil
+}
+
+func (a *App) handleVote(userID, channelID string, args []string) error {
+	if len(args) < 2 {
+		return a.replyError(channelID, "Ошибка: Недостаточно аргументов. Используйте: vote [ID голосования] [номер варианта]")
 	}
 	
+	pollID := args[0]
+	
 	poll, err := a.repository.GetPoll(pollID)
 	if err != nil {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
-		return
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
 	}
 	
-	if poll.IsFinished {
-		a.mmClient.CreatePost(channelID, "Ошибка: Голосование уже завершено.")
-		return
+	if poll.IsClosed() {
+		return a.replyError(channelID, "Ошибка: Голосование уже завершено.")
 	}
 	
-	if optionIdx < 1 || optionIdx > len(poll.Options) {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ошибка: Номер варианта должен быть от 1 до %d.", len(poll.Options)))
-		return
+	if !poll.IsOpen() {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование ещё не открыто.%s", formatOpening(poll)))
 	}
 	
 	vote := models.Vote{
 		PollID:    pollID,
 		UserID:    userID,
-		OptionIdx: optionIdx - 1,
 		VotedAt:   time.Now(),
 	}
 	
-	err = a.repository.AddVote(vote)
-	if err != nil {
+	var choice string
+	
+	switch {
+	case poll.IsQuadratic():
+		allocation, ok := a.parseAllocation(poll, args[1:], channelID)
+		if !ok {
+			return errCommandFailed
+		}
+		vote.OptionID = models.NoOption
+		vote.Allocation = allocation
+		choice = fmt.Sprintf("%s (%d из %d кредитов)", formatAllocation(poll, allocation), vote.Cost(), poll.Credits)
+	case poll.IsSTV():
+		ranking, ok := a.parseRanking(poll, args[1:], channelID)
+		if !ok {
+			return errCommandFailed
+		}
+		vote.OptionID = models.NoOption
+		vote.Ranking = ranking
+		choice = formatRanking(poll, ranking)
+	case poll.IsApproval():
+		approved, ok := a.parseApprovals(poll, args[1:], channelID)
+		if !ok {
+			return errCommandFailed
+		}
+		vote.OptionID = models.NoOption
+		vote.Approved = approved
+		choice = formatApprovals(poll, approved)
+	default:
+		optionIdx, err := strconv.Atoi(args[1])
+		if err != nil {
+			return a.replyError(channelID, "Ошибка: Номер варианта должен быть числом.")
+		}
+		
+		if optionIdx < 1 || optionIdx > len(poll.Options) {
+			return a.replyError(channelID, fmt.Sprintf("Ошибка: Номер варианта должен быть от 1 до %d.", len(poll.Options)))
+		}
+		
+		vote.OptionID = poll.Options[optionIdx-1].ID
+		choice = fmt.Sprintf("вариант %d", optionIdx)
+	}
+	
+	vote, err = a.castVote(poll, vote, "chat")
+	switch {
+	case errors.Is(err, models.ErrNotChannelMember):
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосовать в `%s` могут только участники канала, в котором оно создано.", poll.ID))
+	case errors.Is(err, models.ErrNotEligible):
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Вас нет в списке участников голосования `%s`.", poll.ID))
+	case errors.Is(err, errEligibilityCheck):
+		a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to check voter eligibility")
+		return a.replyError(channelID, "Ошибка при проверке права голоса.")
+	case errors.Is(err, repository.ErrBudgetExceeded):
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Бюллетень стоит %d кредитов, а доступно только %d. Стоимость голосов за вариант — квадрат их числа.", vote.Cost(), poll.Credits))
+	case errors.Is(err, repository.ErrPollFinished):
+		return a.replyError(channelID, "Ошибка: Голосование уже завершено.")
+	case errors.Is(err, repository.ErrPollNotOpen):
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование ещё не открыто.%s", formatOpening(poll)))
+	case err != nil:
 		a.logger.WithError(err).Error("Failed to save vote")
-		a.mmClient.CreatePost(channelID, "Ошибка при сохранении голоса.")
-		return
+		return a.replyError(channelID, "Ошибка при сохранении голоса.")
+	}
+	
+	if poll.Anonymous {
+		a.mmClient.CreatePost(channelID, fmt.Sprintf("Голос в анонимном голосовании `%s` принят.", pollID))
+		return nil
+	}
+	
+	user, err := a.mmClient.GetUser(userID)
+	if err == nil {
+		a.mmClient.CreatePost(channelID, fmt.Sprintf("@%s проголосовал за %s в голосовании `%s`", user.Username, choice, pollID))
+	} else {
+		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ваш голос за %s в голосовании `%s` принят.", choice, pollID))
+	}
+	return nil
+}
+
+func (a *App) handleUnvote(userID, channelID string, args []string) error {
+	if len(args) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. Используйте: unvote [ID голосования]")
+	}
+	
+	pollID := args[0]
+	
+	poll, err := a.repository.GetPoll(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	if poll.IsClosed() {
+		return a.replyError(channelID, "Ошибка: Голосование уже завершено, отозвать голос нельзя.")
+	}
+	
+	err = a.repository.RemoveVote(pollID, userID)
+	if errors.Is(err, repository.ErrVoteNotFound) {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Вы не голосовали в голосовании `%s`.", pollID))
+	}
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to remove vote")
+		return a.replyError(channelID, "Ошибка при отзыве голоса.")
 	}
 	
 	results, err := a.repository.GetPollResults(pollID)
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to get poll results")
-		a.mmClient.CreatePost(channelID, "Ошибка при получении результатов голосования.")
-		return
+		return a.replyError(channelID, "Ошибка при получении результатов голосования.")
 	}
 	
-	formatPollMessage(poll, &results)
+	a.recordAudit(poll.ID, userID, "unvote", "")
+	a.updatePollPost(poll, &results)
 	
-	user, err := a.mmClient.GetUser(userID)
-	if err == nil {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("@%s проголосовал за вариант %d в голосовании `%s`", user.Username, optionIdx, pollID))
-	} else {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ваш голос за вариант %d в голосовании `%s` принят.", optionIdx, pollID))
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Ваш голос в голосовании `%s` отозван.", pollID))
+	return nil
+}
+
+func (a *App) handleEditPoll(userID, channelID string, args []string) error {
+	usage := "Используйте: edit [ID голосования] title \"Заголовок\" | add \"Вариант\" | remove [номер варианта] [--force]"
+	if len(args) < 3 {
+		return a.replyError(channelID, "Ошибка: Недостаточно аргументов. "+usage)
+	}
+	
+	pollID := args[0]
+	action := strings.ToLower(args[1])
+	
+	poll, err := a.repository.GetPoll(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	if !a.permissions.CanManage(userID, poll) {
+		return a.replyError(channelID, "Ошибка: Только создатель голосования или администратор может его редактировать.")
+	}
+	
+	if poll.IsClosed() {
+		return a.replyError(channelID, "Ошибка: Голосование уже завершено.")
+	}
+	
+	if !poll.HasEditableOptions() && (action == "add" || action == "remove") {
+		return a.replyError(channelID, "Ошибка: В голосовании этого типа варианты изменить нельзя.")
 	}
+	
+	var reply, auditDetails string
+	
+	switch action {
+	case "title":
+		parts := splitQuoted(strings.Join(args[2:], " "))
+		if len(parts) == 0 {
+			return a.replyError(channelID, "Ошибка: Укажите новый заголовок в кавычках. "+usage)
+		}
+		
+		auditDetails = fmt.Sprintf("title: %q -> %q", poll.Title, parts[0])
+		poll.Title = parts[0]
+		reply = fmt.Sprintf("Заголовок голосования `%s` изменён.", pollID)
+	case "add":
+		parts := splitQuoted(strings.Join(args[2:], " "))
+		if len(parts) == 0 {
+			return a.replyError(channelID, "Ошибка: Укажите текст варианта в кавычках. "+usage)
+		}
+		
+		option := models.Option{ID: poll.NextOptionID(), Text: parts[0]}
+		poll.Options = append(poll.Options, option)
+		auditDetails = fmt.Sprintf("add option %d: %q", option.ID, option.Text)
+		reply = fmt.Sprintf("Вариант «%s» добавлен в голосование `%s` под номером %d.", parts[0], pollID, len(poll.Options))
+	case "remove":
+		optionIdx, err := strconv.Atoi(args[2])
+		if err != nil {
+			return a.replyError(channelID, "Ошибка: Номер варианта должен быть числом.")
+		}
+		
+		if optionIdx < 1 || optionIdx > len(poll.Options) {
+			return a.replyError(channelID, fmt.Sprintf("Ошибка: Номер варианта должен быть от 1 до %d.", len(poll.Options)))
+		}
+		
+		if len(poll.Options) <= 2 {
+			return a.replyError(channelID, "Ошибка: В голосовании должно остаться минимум 2 варианта.")
+		}
+		
+		removed := poll.Options[optionIdx-1]
+		
+		results, err := a.repository.GetPollResults(pollID)
+		if err != nil {
+			a.logger.WithError(err).Error("Failed to get poll results")
+			return a.replyError(channelID, "Ошибка при получении результатов голосования.")
+		}
+		
+		if count := results.Results[removed.ID]; count > 0 && !hasFlag(args[3:], "--force") {
+			return a.replyError(channelID, fmt.Sprintf("Ошибка: За вариант «%s» уже отдано голосов: %d. Чтобы удалить его вместе с голосами, добавьте `--force`.", removed.Text, count))
+		}
+		
+		poll.Options = append(poll.Options[:optionIdx-1:optionIdx-1], poll.Options[optionIdx:]...)
+		
+		err = a.repository.UpdatePoll(poll)
+		if err != nil {
+			a.logger.WithError(err).Error("Failed to update poll")
+			return a.replyError(channelID, "Ошибка при обновлении голосования.")
+		}
+		
+		affected, err := a.repository.RemoveOptionVotes(pollID, removed.ID)
+		if err != nil {
+			a.logger.WithError(err).Error("Failed to remove option votes")
+		}
+		
+		for _, voterID := range affected {
+			notice := fmt.Sprintf("Вариант «%s», за который вы голосовали в голосовании «%s» (`%s`), был удалён создателем. Ваш голос аннулирован, вы можете проголосовать снова.", removed.Text, poll.Title, pollID)
+			if _, err := a.mmClient.SendDirectMessage(voterID, notice); err != nil {
+				a.logger.WithError(err).WithField("user_id", voterID).Error("Failed to notify voter")
+			}
+		}
+		
+		a.recordAudit(poll.ID, userID, "edit", fmt.Sprintf("remove option %d: %q, votes removed: %d", removed.ID, removed.Text, len(affected)))
+		a.refreshPollPost(poll)
+		a.mmClient.CreatePost(channelID, fmt.Sprintf("Вариант «%s» удалён из голосования `%s`.", removed.Text, pollID))
+		return nil
+	default:
+		return a.replyError(channelID, "Ошибка: Неизвестное действие. "+usage)
+	}
+	
+	err = a.repository.UpdatePoll(poll)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to update poll")
+		return a.replyError(channelID, "Ошибка при обновлении голосования.")
+	}
+	
+	a.recordAudit(poll.ID, userID, "edit", auditDetails)
+	a.refreshPollPost(poll)
+	a.mmClient.CreatePost(channelID, reply)
+	return nil
 }
 
-func (a *App) handleResults(channelID string, args []string) {
+func (a *App) handleWeights(userID, channelID string, args []string) error {
+	usage := "Используйте: weights [ID голосования] [@пользователь вес]"
 	if len(args) < 1 {
-		a.mmClient.CreatePost(channelID, "Ошибка: Укажите ID голосования. Используйте: results [ID голосования]")
-		return
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. "+usage)
+	}
+	
+	pollID := args[0]
+	
+	poll, err := a.repository.GetPoll(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	if len(args) == 1 {
+		return a.replyWeights(poll, channelID)
+	}
+	
+	if len(args) < 3 {
+		return a.replyError(channelID, "Ошибка: Недостаточно аргументов. "+usage)
+	}
+	
+	if poll.CreatorID != userID {
+		return a.replyError(channelID, "Ошибка: Только создатель голосования может назначать веса.")
+	}
+	
+	if poll.IsClosed() {
+		return a.replyError(channelID, "Ошибка: Голосование уже завершено.")
+	}
+	
+	weight, err := strconv.Atoi(args[2])
+	if err != nil || weight < 0 {
+		return a.replyError(channelID, "Ошибка: Вес должен быть неотрицательным целым числом.")
+	}
+	
+	userIDs, missing, err := a.resolveUsernames(args[1:2])
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to resolve user for weight")
+		return a.replyError(channelID, "Ошибка при получении пользователя.")
+	}
+	if len(missing) > 0 {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Пользователь %s не найден.", missing[0]))
+	}
+	
+	err = a.repository.SetWeight(pollID, userIDs[0], weight)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to set weight")
+		return a.replyError(channelID, "Ошибка при сохранении веса.")
+	}
+	
+	a.recordAudit(poll.ID, userID, "weight", fmt.Sprintf("%s: %d", userIDs[0], weight))
+	a.refreshPollPost(poll)
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Вес голоса %s в голосовании `%s`: %d.", args[1], pollID, weight))
+	return nil
+}
+
+func (a *App) replyWeights(poll models.Poll, channelID string) error {
+	weights, err := a.repository.GetWeights(poll.ID)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get weights")
+		return a.replyError(channelID, "Ошибка при получении весов.")
+	}
+	
+	if len(weights) == 0 {
+		a.mmClient.CreatePost(channelID, fmt.Sprintf("В голосовании `%s` веса не назначены. Веса групп из конфигурации применяются при голосовании.", poll.ID))
+		return nil
+	}
+	
+	userIDs := make([]string, 0, len(weights))
+	for voterID := range weights {
+		userIDs = append(userIDs, voterID)
+	}
+	sort.Strings(userIDs)
+	
+	message := fmt.Sprintf("### Веса голосов: %s\n", poll.Title)
+	for _, voterID := range userIDs {
+		name := voterID
+		if user, err := a.mmClient.GetUser(voterID); err == nil {
+			name = "@" + user.Username
+		}
+		message += fmt.Sprintf("- %s: %d\n", name, weights[voterID])
+	}
+	
+	a.mmClient.CreatePost(channelID, message)
+	return nil
+}
+
+func (a *App) handleResults(channelID string, args []string) error {
+	if len(args) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. Используйте: results [ID голосования]")
 	}
 	
 	pollID := args[0]
 	
 	results, err := a.repository.GetPollResults(pollID)
 	if err != nil {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
-		return
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
 	}
 	
 	message := formatResultsMessage(results)
+	if results.Archived {
+		message += "\n\n_Голосование перенесено в архив: результаты доступны только для просмотра._"
+	}
 	
-	a.mmClient.CreatePost(channelID, message)
+	a.postResults(channelID, message, results)
+	return nil
 }
 
-func (a *App) handleFinishPoll(userID, channelID string, args []string) {
+func (a *App) handleFinishPoll(userID, channelID string, args []string) error {
 	if len(args) < 1 {
-		a.mmClient.CreatePost(channelID, "Ошибка: Укажите ID голосования. Используйте: finish [ID голосования]")
-		return
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. Используйте: finish [ID голосования]")
 	}
 	
 	pollID := args[0]
 	
 	poll, err := a.repository.GetPoll(pollID)
 	if err != nil {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
-		return
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
 	}
 	
-	if poll.CreatorID != userID {
-		a.mmClient.CreatePost(channelID, "Ошибка: Только создатель голосования может его завершить.")
-		return
+	if !a.permissions.CanManage(userID, poll) {
+		return a.replyError(channelID, "Ошибка: Только создатель голосования или администратор может его завершить.")
 	}
 	
-	if poll.IsFinished {
+	if poll.IsClosed() {
 		a.mmClient.CreatePost(channelID, "Голосование уже завершено.")
-		return
+		return nil
+	}
+	
+	results, err := a.finishPoll(poll, userID)
+	if errors.Is(err, errPollNotOpen) {
+		a.mmClient.CreatePost(channelID, "Голосование уже завершено.")
+		return nil
+	}
+	if errors.Is(err, errResultsUnavailable) {
+		return a.replyError(channelID, "Ошибка при получении результатов голосования.")
+	}
+	if err != nil {
+		return a.replyError(channelID, "Ошибка при обновлении голосования.")
+	}
+	
+	message := formatResultsMessage(results)
+	message = "### Голосование завершено!\n" + message
+	
+	a.postResults(channelID, message, results)
+	return nil
+}
+
+func (a *App) handleReopenPoll(userID, channelID string, args []string) error {
+	if len(args) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. Используйте: reopen [ID голосования] [--for 1d]")
+	}
+	
+	pollID := args[0]
+	_, flags := parseCommandArgs(args[1:])
+	
+	poll, err := a.repository.GetPoll(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	if !a.permissions.CanManage(userID, poll) {
+		return a.replyError(channelID, "Ошибка: Только создатель голосования или администратор может открыть его заново.")
+	}
+	
+	if !poll.IsClosed() {
+		a.mmClient.CreatePost(channelID, "Голосование ещё не завершено.")
+		return nil
+	}
+	
+	now := time.Now()
+	details := "deadline cleared"
+	
+	if value, ok := flagValue(flags, "for"); ok {
+		duration, err := parseDuration(value)
+		if err != nil {
+			return a.replyError(channelID, "Ошибка: Некорректная длительность для --for. Примеры: 30m, 4h, 1d.")
+		}
+		poll.ClosesAt = now.Add(duration)
+		details = "closes at " + poll.ClosesAt.Format(time.RFC3339)
+	} else if !poll.ClosesAt.After(now) {
+		// Иначе голосование тут же закроется снова по старому сроку
+		poll.ClosesAt = time.Time{}
+	} else {
+		details = "closes at " + poll.ClosesAt.Format(time.RFC3339)
 	}
 	
-	poll.IsFinished = true
-	poll.FinishedAt = time.Now()
+	poll.Status = models.PollStatusOpen
+	poll.FinishedAt = time.Time{}
+	poll.RemindersSent = nil
 	
 	err = a.repository.UpdatePoll(poll)
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to update poll")
-		a.mmClient.CreatePost(channelID, "Ошибка при обновлении голосования.")
-		return
+		return a.replyError(channelID, "Ошибка при обновлении голосования.")
+	}
+	
+	a.recordAudit(poll.ID, userID, "reopen", details)
+	a.refreshPollPost(poll)
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Голосование `%s` снова открыто.%s", pollID, formatDeadline(poll)))
+	return nil
+}
+
+func (a *App) handleExtendPoll(userID, channelID string, args []string) error {
+	usage := "Используйте: extend [ID голосования] --for 1d"
+	if len(args) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. "+usage)
+	}
+	
+	pollID := args[0]
+	_, flags := parseCommandArgs(args[1:])
+	
+	value, ok := flagValue(flags, "for")
+	if !ok || value == "" {
+		return a.replyError(channelID, "Ошибка: Укажите, на сколько продлить голосование. "+usage)
+	}
+	
+	duration, err := parseDuration(value)
+	if err != nil {
+		return a.replyError(channelID, "Ошибка: Некорректная длительность для --for. Примеры: 30m, 4h, 1d.")
+	}
+	
+	poll, err := a.repository.GetPoll(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	if !a.permissions.CanManage(userID, poll) {
+		return a.replyError(channelID, "Ошибка: Только создатель голосования или администратор может продлить его.")
+	}
+	
+	now := time.Now()
+	wasFinished := poll.IsClosed()
+	
+	if !wasFinished && poll.ClosesAt.After(now) {
+		poll.ClosesAt = poll.ClosesAt.Add(duration)
+	} else {
+		poll.ClosesAt = now.Add(duration)
+	}
+	
+	if wasFinished {
+		poll.Status = models.PollStatusOpen
+		poll.FinishedAt = time.Time{}
+	}
+	// Напоминания отсчитываются от нового срока
+	poll.RemindersSent = nil
+	
+	err = a.repository.UpdatePoll(poll)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to update poll")
+		return a.replyError(channelID, "Ошибка при обновлении голосования.")
+	}
+	
+	details := "closes at " + poll.ClosesAt.Format(time.RFC3339)
+	if wasFinished {
+		a.recordAudit(poll.ID, userID, "reopen", details)
+	}
+	a.recordAudit(poll.ID, userID, "extend", "+"+duration.String()+", "+details)
+	a.refreshPollPost(poll)
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Голосование `%s` продлено.%s", pollID, formatDeadline(poll)))
+	return nil
+}
+
+func (a *App) handleDecide(userID, channelID string, args []string) error {
+	if len(args) < 2 {
+		return a.replyError(channelID, "Ошибка: Недостаточно аргументов. Используйте: decide [ID голосования] [номер варианта]")
+	}
+	
+	pollID := args[0]
+	
+	optionIdx, err := strconv.Atoi(args[1])
+	if err != nil {
+		return a.replyError(channelID, "Ошибка: Номер варианта должен быть числом.")
 	}
 	
 	results, err := a.repository.GetPollResults(pollID)
 	if err != nil {
-		a.logger.WithError(err).Error("Failed to get poll results")
-		a.mmClient.CreatePost(channelID, "Ошибка при получении результатов голосования.")
-		return
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
 	}
 	
-	message := formatResultsMessage(results)
-	message = "### Голосование завершено!\n" + message
+	poll := results.Poll
 	
-	a.mmClient.CreatePost(channelID, message)
+	if poll.CreatorID != userID {
+		return a.replyError(channelID, "Ошибка: Решающий голос может отдать только создатель голосования.")
+	}
+	
+	if poll.TieBreak != models.TieBreakCreator {
+		return a.replyError(channelID, "Ошибка: В этом голосовании ничья не разрешается решающим голосом создателя.")
+	}
+	
+	if results.Winner == nil || !results.Winner.Tie {
+		return a.replyError(channelID, "Ошибка: В голосовании нет ничьей.")
+	}
+	
+	if optionIdx < 1 || optionIdx > len(poll.Options) {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Номер варианта должен быть от 1 до %d.", len(poll.Options)))
+	}
+	
+	option := poll.Options[optionIdx-1]
+	if !slices.Contains(results.Winner.TiedOptionIDs, option.ID) {
+		return a.replyError(channelID, "Ошибка: Решающий голос можно отдать только за один из вариантов, разделивших первое место.")
+	}
+	
+	poll.CastingVote = &option.ID
+	
+	err = a.repository.UpdatePoll(poll)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to update poll")
+		return a.replyError(channelID, "Ошибка при обновлении голосования.")
+	}
+	
+	a.recordAudit(poll.ID, userID, "casting_vote", fmt.Sprintf("option %d: %q", option.ID, option.Text))
+	a.refreshPollPost(poll)
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Решающий голос создателя отдан за «%s» в голосовании `%s`.", option.Text, pollID))
+	return nil
 }
 
-func (a *App) handleDeletePoll(userID, channelID string, args []string) {
+func (a *App) handleDeletePoll(userID, channelID string, args []string) error {
 	if len(args) < 1 {
-		a.mmClient.CreatePost(channelID, "Ошибка: Укажите ID голосования. Используйте: delete [ID голосования]")
-		return
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования. Используйте: delete [ID голосования]")
 	}
 	
 	pollID := args[0]
 	
 	poll, err := a.repository.GetPoll(pollID)
 	if err != nil {
-		a.mmClient.CreatePost(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
-		return
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
 	}
 	
-	if poll.CreatorID != userID {
-		a.mmClient.CreatePost(channelID, "Ошибка: Только создатель голосования может его удалить.")
-		return
+	if !a.permissions.CanManage(userID, poll) {
+		return a.replyError(channelID, "Ошибка: Только создатель голосования или администратор может его удалить.")
 	}
 	
-	err = a.repository.DeletePoll(pollID)
+	// Голоса, веса и журнал удаляются вместе с голосованием, чтобы не остаться без владельца
+	votes, err := a.repository.PurgePoll(pollID)
 	if err != nil {
 		a.logger.WithError(err).Error("Failed to delete poll")
-		a.mmClient.CreatePost(channelID, "Ошибка при удалении голосования.")
+		return a.replyError(channelID, "Ошибка при удалении голосования.")
+	}
+	
+	a.logger.WithField("poll_id", pollID).WithField("votes", votes).Info("Poll deleted")
+	a.emit(models.EventPollDeleted, poll, userID)
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Голосование с ID `%s` успешно удалено вместе с голосами.", pollID))
+	return nil
+}
+
+func (a *App) resolveUsernames(usernames []string) ([]string, []string, error) {
+	names := make([]string, 0, len(usernames))
+	for _, name := range usernames {
+		names = append(names, strings.ToLower(strings.TrimPrefix(name, "@")))
+	}
+	
+	users, err := a.mmClient.GetUsersByUsernames(names)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	found := make(map[string]string, len(users))
+	for _, user := range users {
+		found[strings.ToLower(user.Username)] = user.Id
+	}
+	
+	var userIDs, missing []string
+	for _, name := range names {
+		if userID, ok := found[name]; ok {
+			userIDs = append(userIDs, userID)
+		} else {
+			missing = append(missing, "@"+name)
+		}
+	}
+	
+	return userIDs, missing, nil
+}
+
+func (a *App) updatePollPost(poll models.Poll, results *models.PollResults) {
+	if poll.PostID == "" {
 		return
 	}
 	
-	a.mmClient.CreatePost(channelID, fmt.Sprintf("Голосование с ID `%s` успешно удалено.", pollID))
+	post := &model.Post{
+		Id:        poll.PostID,
+		ChannelId: poll.ChannelID,
+		Message:   formatPollMessage(poll, results),
+	}
+	
+	if _, err := a.mmClient.UpdatePost(post); err != nil {
+		a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to update poll post")
+	}
+}
+
+func (a *App) refreshPollPost(poll models.Poll) {
+	results, err := a.repository.GetPollResults(poll.ID)
+	if err != nil {
+		a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to get poll results")
+		a.updatePollPost(poll, nil)
+		return
+	}
+	
+	a.updatePollPost(results.Poll, &results)
+}
+
+func formatProposalResults(results models.PollResults) string {
+	message := fmt.Sprintf("### Итоги предложения: %s\n", results.Poll.Title)
+	message += fmt.Sprintf("**ID голосования**: `%s`\n\n", results.Poll.ID)
+	
+	labels := []string{"✅ **За**", "❌ **Против**", "⚪ **Воздержались**"}
+	for i, optionID := range []int{models.ProposalYes, models.ProposalNo, models.ProposalAbstain} {
+		message += fmt.Sprintf("%s: %d", labels[i], results.Results[optionID])
+		if results.Weighted {
+			message += fmt.Sprintf(" (вес %d)", results.WeightedResults[optionID])
+		}
+		message += "\n"
+	}
+	
+	tally := results.Tally()
+	yes, no := tally[models.ProposalYes], tally[models.ProposalNo]
+	
+	if yes+no > 0 {
+		message += fmt.Sprintf("\n**Одобрение**: %.1f%% (за / (за + против), воздержавшиеся не учитываются)", results.Approval)
+	} else {
+		message += "\n**Одобрение**: нет голосов «за» или «против»"
+	}
+	message += fmt.Sprintf("\n**Всего голосов**: %d", results.TotalVotes)
+	message += formatOutcome(results)
+	
+	message += formatStatus(results.Poll)
+	
+	return message
+}
+
+// formatSTVResults выводит избранных кандидатов и ход подсчёта по раундам.
+func formatSTVResults(results models.PollResults) string {
+	poll := results.Poll
+	stv := results.STV
+	
+	message := fmt.Sprintf("### Результаты выборов: %s\n", poll.Title)
+	message += fmt.Sprintf("**ID голосования**: `%s`\n\n", poll.ID)
+	
+	if stv == nil || stv.Ballots == 0 {
+		message += "Действительных бюллетеней пока нет.\n"
+	} else {
+		message += fmt.Sprintf("**Мест**: %d, **бюллетеней**: %s, **квота Друпа**: %s\n\n", stv.Seats, formatVotes(stv.Ballots), formatVotes(stv.Quota))
+		
+		for _, round := range stv.Rounds {
+			var tallies []string
+			for i, option := range poll.Options {
+				if votes, ok := round.Tallies[option.ID]; ok {
+					tallies = append(tallies, fmt.Sprintf("%d. %s — %s", i+1, option.Text, formatVotes(votes)))
+				}
+			}
+			message += fmt.Sprintf("**Раунд %d**: %s", round.Number, strings.Join(tallies, "; "))
+			if round.Exhausted > 0 {
+				message += fmt.Sprintf("; исчерпано — %s", formatVotes(round.Exhausted))
+			}
+			message += "\n"
+			
+			for _, optionID := range round.Elected {
+				option, _ := poll.OptionByID(optionID)
+				message += fmt.Sprintf("→ избран(а) «%s»\n", option.Text)
+			}
+			for _, optionID := range round.Eliminated {
+				option, _ := poll.OptionByID(optionID)
+				message += fmt.Sprintf("→ выбывает «%s»\n", option.Text)
+			}
+			for _, transfer := range round.Transfers {
+				if transfer.Votes == 0 {
+					continue
+				}
+				option, _ := poll.OptionByID(transfer.From)
+				if transfer.Factor < 1 {
+					message += fmt.Sprintf("→ излишек «%s» (%s) передан с коэффициентом %.4f\n", option.Text, formatVotes(transfer.Votes), transfer.Factor)
+				} else {
+					message += fmt.Sprintf("→ голоса «%s» (%s) переданы следующим предпочтениям\n", option.Text, formatVotes(transfer.Votes))
+				}
+			}
+		}
+		
+		elected := make([]string, 0, len(stv.Elected))
+		for _, optionID := range stv.Elected {
+			option, _ := poll.OptionByID(optionID)
+			elected = append(elected, "«"+option.Text+"»")
+		}
+		if len(elected) > 0 {
+			message += fmt.Sprintf("\n**Избраны**: %s", strings.Join(elected, ", "))
+		}
+	}
+	
+	message += fmt.Sprintf("\n**Проголосовало**: %d", results.TotalVotes)
+	if results.Weighted {
+		message += "\n**Учитываются веса голосующих**"
+	}
+	
+	if poll.IsBinding() {
+		message += formatOutcome(results)
+	}
+	
+	message += formatStatus(poll)
+	
+	return message
+}
+
+// formatApprovalResults выводит варианты по убыванию числа одобрений;
+// процент считается от числа проголосовавших, а не от числа отметок.
+func formatApprovalResults(results models.PollResults) string {
+	poll := results.Poll
+	
+	message := fmt.Sprintf("### Результаты голосования одобрением: %s\n", poll.Title)
+	message += fmt.Sprintf("**ID голосования**: `%s`\n\n", poll.ID)
+	
+	for place, optionID := range results.Ranking {
+		option, _ := poll.OptionByID(optionID)
+		message += fmt.Sprintf("%d. **%s**: %d одобрений", place+1, option.Text, results.Results[optionID])
+		if results.Weighted {
+			message += fmt.Sprintf(", вес %d", results.WeightedResults[optionID])
+		}
+		message += fmt.Sprintf(" (%.1f%% проголосовавших)\n", results.ApprovalRates[optionID])
+	}
+	
+	message += fmt.Sprintf("\n**Проголосовало**: %d", results.TotalVotes)
+	if results.Weighted {
+		message += fmt.Sprintf("\n**Суммарный вес**: %d (итог считается по весам)", results.TotalWeight)
+	}
+	message += formatWinner(results)
+	
+	if poll.IsBinding() {
+		message += formatOutcome(results)
+	}
+	
+	message += formatStatus(poll)
+	
+	return message
+}
+
+// formatVotes печатает дробное число голосов без лишних нулей.
+func formatVotes(votes float64) string {
+	return strconv.FormatFloat(votes, 'f', -1, 64)
+}
+
+func formatRequirements(poll models.Poll) string {
+	if !poll.IsBinding() {
+		return ""
+	}
+	
+	message := ""
+	if !poll.IsSTV() {
+		message += fmt.Sprintf("\n**Порог принятия**: %s", thresholdName(poll.Threshold))
+	}
+	if poll.Quorum.IsSet() {
+		message += fmt.Sprintf("\n**Кворум**: %s", poll.Quorum)
+	}
+	return message
+}
+
+var thresholdNames = map[string]string{
+	models.ThresholdMajority:  "простое большинство",
+	models.ThresholdTwoThirds: "2/3 голосов",
+	models.ThresholdUnanimity: "единогласно",
 }
 
+func thresholdName(threshold string) string {
+	if name, ok := thresholdNames[threshold]; ok {
+		return name
+	}
+	return "не менее " + threshold
+}
+
+func formatOutcome(results models.PollResults) string {
+	message := ""
+	if results.Electorate > 0 {
+		message += fmt.Sprintf("\n**Явка**: %d из %d (%.1f%%)", results.TotalVotes, results.Electorate, results.Turnout)
+	}
+	
+	if results.Poll.Quorum.Count == 0 && results.Poll.Quorum.Percent > 0 && results.Electorate == 0 {
+		message += "\n**Кворум**: не проверен — число участников неизвестно"
+	} else if results.Poll.Quorum.IsSet() {
+		status := "достигнут"
+		if !results.QuorumMet {
+			status = "не достигнут"
+		}
+		message += fmt.Sprintf("\n**Кворум**: %s (нужно голосов: %d)", status, results.QuorumRequired)
+	}
+	
+	if !results.Poll.IsSTV() {
+		message += fmt.Sprintf("\n**Порог принятия**: %s", thresholdName(results.Poll.Threshold))
+	}
+	
+	label := "**Итог**"
+	if !results.Poll.IsClosed() {
+		label = "**Итог (предварительно)**"
+	}
+	
+	switch {
+	case results.Outcome == models.OutcomePassed && results.Poll.IsProposal():
+		message += fmt.Sprintf("\n%s: ✅ Предложение принято", label)
+	case results.Outcome == models.OutcomeFailed && results.Poll.IsProposal():
+		message += fmt.Sprintf("\n%s: ❌ Предложение отклонено — одобрение ниже порога", label)
+	case results.Outcome == models.OutcomePassed && results.Poll.IsSTV():
+		message += fmt.Sprintf("\n%s: ✅ Выборы состоялись — все места заняты", label)
+	case results.Outcome == models.OutcomeFailed && results.Poll.IsSTV():
+		message += fmt.Sprintf("\n%s: ❌ Выборы не состоялись — заняты не все места", label)
+	case results.Outcome == models.OutcomePassed:
+		option, _ := results.Poll.OptionByID(results.Winner.OptionID)
+		message += fmt.Sprintf("\n%s: ✅ Решение принято — «%s»", label, option.Text)
+	case results.Outcome == models.OutcomeFailed:
+		message += fmt.Sprintf("\n%s: ❌ Решение не принято — ни один вариант не набрал нужного порога", label)
+	case results.Outcome == models.OutcomeInvalid:
+		message += fmt.Sprintf("\n%s: ⚠️ Голосование недействительно — нет кворума", label)
+	}
+	
+	return message
+}
+
+func formatWinner(results models.PollResults) string {
+	winner := results.Winner
+	if winner == nil {
+		return ""
+	}
+	
+	if !winner.Tie {
+		option, _ := results.Poll.OptionByID(winner.OptionID)
+		return fmt.Sprintf("\n**Победитель**: «%s»", option.Text)
+	}
+	
+	tied := make([]string, 0, len(winner.TiedOptionIDs))
+	for _, optionID := range winner.TiedOptionIDs {
+		option, _ := results.Poll.OptionByID(optionID)
+		tied = append(tied, "«"+option.Text+"»")
+	}
+	message := fmt.Sprintf("\n**Ничья** (по %d голосов): %s", winner.Votes, strings.Join(tied, ", "))
+	
+	if !winner.Resolved {
+		if winner.TieBreak == models.TieBreakCreator {
+			message += fmt.Sprintf("\nОжидается решающий голос создателя: `decide %s [номер варианта]`", results.Poll.ID)
+		}
+		return message
+	}
+	
+	option, _ := results.Poll.OptionByID(winner.OptionID)
+	switch winner.TieBreak {
+	case models.TieBreakCreator:
+		message += fmt.Sprintf("\n**Победитель**: «%s» (решающий голос создателя)", option.Text)
+	case models.TieBreakEarliest:
+		message += fmt.Sprintf("\n**Победитель**: «%s» (первым набрал итоговое число голосов)", option.Text)
+	case models.TieBreakRandom:
+		message += fmt.Sprintf("\n**Победитель**: «%s» (жребий, seed `%d`)", option.Text, winner.Seed)
+	}
+	return message
+}
+
+func formatOpening(poll models.Poll) string {
+	if poll.OpensAt.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("\n**Откроется**: %s", poll.OpensAt.Format("02.01.2006 15:04"))
+}
+
+func formatStatus(poll models.Poll) string {
+	switch poll.Status {
+	case models.PollStatusClosed:
+		message := "\n**Статус**: Завершено"
+		if !poll.FinishedAt.IsZero() {
+			message += fmt.Sprintf(" (%s)", poll.FinishedAt.Format("02.01.2006 15:04:05"))
+		}
+		return message
+	case models.PollStatusScheduled, models.PollStatusDraft:
+		return "\n**Статус**: Ожидает открытия" + formatOpening(poll)
+	default:
+		return "\n**Статус**: Активно"
+	}
+}
+
+func formatDeadline(poll models.Poll) string {
+	if poll.ClosesAt.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("\n**Завершится**: %s", poll.ClosesAt.Format("02.01.2006 15:04"))
+}
+
+func hasFlag(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
 
 func splitQuoted(s string) []string {
 	var result []string
@@ -243,31 +1316,75 @@ func splitQuoted(s string) []string {
 
 func formatPollMessage(poll models.Poll, results *models.PollResults) string {
 	message := fmt.Sprintf("### %s\n", poll.Title)
+	if poll.IsProposal() {
+		message = fmt.Sprintf("### Предложение: %s\n", poll.Title)
+	}
 	message += fmt.Sprintf("**ID голосования**: `%s`\n\n", poll.ID)
 	
 	for i, option := range poll.Options {
 		count := 0
 		if results != nil {
-			count = results.Results[option]
+			count = results.Results[option.ID]
 		}
 		
-		message += fmt.Sprintf("%d. %s", i+1, option)
+		message += fmt.Sprintf("%d. %s", i+1, option.Text)
 		if results != nil {
-			message += fmt.Sprintf(" (%d голосов)", count)
+			message += fmt.Sprintf(" (%d голосов", count)
+			if results.Weighted {
+				message += fmt.Sprintf(", вес %d", results.WeightedResults[option.ID])
+			}
+			message += ")"
 		}
 		message += "\n"
 	}
 	
-	message += "\nДля голосования отправьте: `vote " + poll.ID + " [номер варианта]`"
+	if poll.IsQuadratic() {
+		message += fmt.Sprintf("\nДля голосования отправьте: `vote %s [номер:голоса] ...`, например `vote %s 1:3 2:1`", poll.ID, poll.ID)
+		message += fmt.Sprintf("\n**Бюджет**: %d кредитов на участника, стоимость N голосов за вариант — N²", poll.Credits)
+	} else if poll.IsSTV() {
+		message += fmt.Sprintf("\nДля голосования отправьте номера кандидатов в порядке предпочтения: `vote %s 3 1 2`", poll.ID)
+		message += fmt.Sprintf("\n**Мест**: %d, подсчёт методом единого передаваемого голоса (квота Друпа)", poll.Seats)
+	} else if poll.IsApproval() {
+		message += fmt.Sprintf("\nДля голосования отправьте номера всех приемлемых для вас вариантов: `vote %s 1 2 5`", poll.ID)
+	} else {
+		message += "\nДля голосования отправьте: `vote " + poll.ID + " [номер варианта]`"
+	}
+	
+	if poll.HasVoterList() {
+		message += "\n**Голосовать могут**: только участники из списка голосования"
+	}
+	
+	if poll.Anonymous {
+		message += "\n**Анонимное голосование**: видны только итоги"
+	}
+	
+	message += formatRequirements(poll)
 	
-	if poll.IsFinished {
+	switch poll.Status {
+	case models.PollStatusScheduled:
+		message += formatOpening(poll)
+		message += formatDeadline(poll)
+		message += "\n\n**Голосование ещё не открыто**"
+	case models.PollStatusClosed:
 		message += "\n\n**Голосование завершено!**"
+	default:
+		message += formatDeadline(poll)
 	}
 	
 	return message
 }
 
 func formatResultsMessage(results models.PollResults) string {
+	if results.Poll.IsProposal() {
+		return formatProposalResults(results)
+	}
+	if results.Poll.IsSTV() {
+		return formatSTVResults(results)
+	}
+	if results.Poll.IsApproval() {
+		return formatApprovalResults(results)
+	}
+	
 	message := fmt.Sprintf("### Результаты голосования: %s\n", results.Poll.Title)
 	message += fmt.Sprintf("**ID голосования**: `%s`\n\n", results.Poll.ID)
 	
@@ -277,25 +1394,44 @@ func formatResultsMessage(results models.PollResults) string {
 	}
 	
 	for i, option := range results.Poll.Options {
-		count := results.Results[option]
+		count := results.Results[option.ID]
 		percentage := 0.0
 		if totalVotes > 0 {
 			percentage = float64(count) / float64(totalVotes) * 100
 		}
 		
-		message += fmt.Sprintf("%d. **%s**: %d голосов (%.1f%%)\n", i+1, option, count, percentage)
+		if results.Poll.IsQuadratic() {
+			message += fmt.Sprintf("%d. **%s**: %d голосов (%.1f%%), потрачено кредитов: %d\n", i+1, option.Text, count, percentage, results.CreditsSpent[option.ID])
+			continue
+		}
+		
+		if results.Weighted {
+			weight := results.WeightedResults[option.ID]
+			if results.TotalWeight > 0 {
+				percentage = float64(weight) / float64(results.TotalWeight) * 100
+			}
+			message += fmt.Sprintf("%d. **%s**: %d голосов, вес %d (%.1f%%)\n", i+1, option.Text, count, weight, percentage)
+			continue
+		}
+		
+		message += fmt.Sprintf("%d. **%s**: %d голосов (%.1f%%)\n", i+1, option.Text, count, percentage)
 	}
 	
-	message += fmt.Sprintf("\n**Всего голосов**: %d", totalVotes)
-	
-	if results.Poll.IsFinished {
-		message += "\n**Статус**: Завершено"
-		if !results.Poll.FinishedAt.IsZero() {
-			message += fmt.Sprintf(" (%s)", results.Poll.FinishedAt.Format("02.01.2006 15:04:05"))
-		}
+	if results.Poll.IsQuadratic() {
+		message += fmt.Sprintf("\n**Участников**: %d, **голосов**: %d, **потрачено кредитов**: %d", results.TotalVotes, totalVotes, results.TotalCredits)
 	} else {
-		message += "\n**Статус**: Активно"
+		message += fmt.Sprintf("\n**Всего голосов**: %d", totalVotes)
+	}
+	if results.Weighted {
+		message += fmt.Sprintf("\n**Суммарный вес**: %d (итог считается по весам)", results.TotalWeight)
 	}
+	message += formatWinner(results)
+	
+	if results.Poll.IsBinding() {
+		message += formatOutcome(results)
+	}
+	
+	message += formatStatus(results.Poll)
 	
 	return message
 }
\ No newline at end of file
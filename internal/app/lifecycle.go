@@ -0,0 +1,158 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
+)
+
+const deadlineCheckInterval = 30 * time.Second
+
+var (
+	errResultsUnavailable = errors.New("poll results unavailable")
+	errPollNotOpen        = errors.New("poll is no longer open")
+)
+
+// finishPoll завершает голосование, фиксирует это в журнале и обновляет пост.
+// userID пуст, если голосование закрыто автоматически по сроку.
+func (a *App) finishPoll(poll models.Poll, userID string) (models.PollResults, error) {
+	poll.Status = models.PollStatusClosed
+	poll.FinishedAt = time.Now()
+	
+	// Итог считается по составу канала на момент завершения
+	a.refreshElectorate(&poll)
+	
+	// Закрывает только тот, кто застал голосование открытым: ручное
+	// завершение, срок и reopen не должны завершить его дважды
+	finished, err := a.repository.FinishPoll(poll.ID, poll.FinishedAt, poll.Electorate)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to update poll")
+		return models.PollResults{}, err
+	}
+	if !finished {
+		a.logger.WithField("poll_id", poll.ID).Info("Poll is no longer open, skipping finish")
+		return models.PollResults{}, errPollNotOpen
+	}
+	
+	action, trigger := "finish", "manual"
+	if userID == "" {
+		action, trigger = "auto_finish", "deadline"
+	}
+	a.recordAudit(poll.ID, userID, action, "")
+	monitoring.PollsFinished.WithLabelValues(trigger).Inc()
+	
+	results, err := a.repository.GetPollResults(poll.ID)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get poll results")
+		a.updatePollPost(poll, nil)
+		a.emit(models.EventPollFinished, poll, userID)
+		return models.PollResults{}, errResultsUnavailable
+	}
+	
+	a.updatePollPost(results.Poll, &results)
+	a.emitFinished(results, userID)
+	
+	return results, nil
+}
+
+func (a *App) refreshElectorate(poll *models.Poll) {
+	eligible, err := a.eligibility.EligibleVoters(*poll)
+	if err != nil {
+		a.logger.WithError(err).WithField("poll_id", poll.ID).Warn("Failed to count eligible voters")
+		return
+	}
+	poll.Electorate = len(eligible)
+}
+
+func (a *App) recordAudit(pollID, userID, action, details string) {
+	entry := models.AuditEntry{
+		ID:        uuid.New().String(),
+		PollID:    pollID,
+		UserID:    userID,
+		Action:    action,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+	
+	if err := a.repository.AddAuditEntry(entry); err != nil {
+		a.logger.WithError(err).WithField("poll_id", pollID).Error("Failed to record audit entry")
+	}
+}
+
+func (a *App) watchDeadlines() {
+	ticker := time.NewTicker(deadlineCheckInterval)
+	defer ticker.Stop()
+	
+	for range ticker.C {
+		a.openScheduledPolls()
+		a.sendDueReminders()
+		a.closeExpiredPolls()
+	}
+}
+
+// openScheduledPolls открывает объявленные заранее голосования,
+// время открытия которых наступило.
+func (a *App) openScheduledPolls() {
+	polls, err := a.repository.ListPolls()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list polls for opening check")
+		return
+	}
+	
+	now := time.Now()
+	for _, poll := range polls {
+		if !poll.IsScheduled() || poll.OpensAt.After(now) {
+			continue
+		}
+		
+		a.logger.WithField("poll_id", poll.ID).Info("Opening scheduled poll")
+		
+		opened, err := a.repository.SetPollStatus(poll.ID, models.PollStatusScheduled, models.PollStatusOpen)
+		if err != nil {
+			a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to open poll")
+			continue
+		}
+		if !opened {
+			a.logger.WithField("poll_id", poll.ID).Info("Poll is no longer scheduled, skipping")
+			continue
+		}
+		
+		poll.Status = models.PollStatusOpen
+		
+		a.recordAudit(poll.ID, "", "auto_open", "")
+		a.refreshPollPost(poll)
+		
+		a.mmClient.CreatePost(poll.ChannelID, fmt.Sprintf("### Голосование «%s» открыто!\nДля голосования отправьте: `vote %s ...`, варианты — в сообщении голосования.%s", poll.Title, poll.ID, formatDeadline(poll)))
+	}
+}
+
+func (a *App) closeExpiredPolls() {
+	polls, err := a.repository.ListPolls()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list polls for deadline check")
+		return
+	}
+	
+	now := time.Now()
+	for _, poll := range polls {
+		if !poll.IsOpen() || poll.ClosesAt.IsZero() || poll.ClosesAt.After(now) {
+			continue
+		}
+		
+		a.logger.WithField("poll_id", poll.ID).Info("Closing poll by deadline")
+		
+		results, err := a.finishPoll(poll, "")
+		if err != nil {
+			continue
+		}
+		
+		message := formatResultsMessage(results)
+		message = "### Голосование завершено по истечении срока!\n" + message
+		
+		a.postResults(poll.ChannelID, message, results)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"github.com/sirupsen/logrus"
+	"github.com/dew-77/mattermost-vote-system/internal/mattermost"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// PermissionService решает, может ли пользователь управлять голосованием:
+// завершать, удалять, редактировать и открывать его заново.
+type PermissionService struct {
+	mmClient   *mattermost.Client
+	logger     *logrus.Logger
+	moderators map[string]bool
+}
+
+func NewPermissionService(mmClient *mattermost.Client, logger *logrus.Logger, moderators []string) *PermissionService {
+	allowed := make(map[string]bool, len(moderators))
+	for _, moderator := range moderators {
+		moderator = strings.TrimPrefix(strings.TrimSpace(moderator), "@")
+		if moderator != "" {
+			allowed[strings.ToLower(moderator)] = true
+		}
+	}
+	
+	return &PermissionService{
+		mmClient:   mmClient,
+		logger:     logger,
+		moderators: allowed,
+	}
+}
+
+func (p *PermissionService) CanManage(userID string, poll models.Poll) bool {
+	return p.canManage(userID, poll.CreatorID, poll.ChannelID)
+}
+
+// CanManageSchedule применяет к расписанию те же правила, что и к голосованию.
+func (p *PermissionService) CanManageSchedule(userID string, schedule models.Schedule) bool {
+	return p.canManage(userID, schedule.CreatorID, schedule.ChannelID)
+}
+
+func (p *PermissionService) canManage(userID, creatorID, channelID string) bool {
+	if creatorID == userID {
+		return true
+	}
+	
+	if p.moderators[strings.ToLower(userID)] {
+		return true
+	}
+	
+	user, err := p.mmClient.GetUser(userID)
+	if err != nil {
+		p.logger.WithError(err).WithField("user_id", userID).Warn("Failed to get user for permission check")
+		return false
+	}
+	
+	if p.moderators[strings.ToLower(user.Username)] || user.IsSystemAdmin() {
+		return true
+	}
+	
+	if member, err := p.mmClient.GetChannelMember(channelID, userID); err == nil {
+		if member.SchemeAdmin || hasRole(member.Roles, model.ChannelAdminRoleId) {
+			return true
+		}
+	}
+	
+	if member, err := p.mmClient.GetTeamMember(userID); err == nil {
+		if member.SchemeAdmin || hasRole(member.Roles, model.TeamAdminRoleId) {
+			return true
+		}
+	}
+	
+	return false
+}
+
+func hasRole(roles, role string) bool {
+	for _, r := range strings.Fields(roles) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
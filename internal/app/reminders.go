@@ -0,0 +1,178 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// parseReminders разбирает смещения напоминаний вида 1h, 15m, 1d
+// и упорядочивает их от самого раннего напоминания к позднему.
+func parseReminders(values []string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			
+			offset, err := parseDuration(part)
+			if err != nil {
+				return nil, err
+			}
+			offsets = append(offsets, offset)
+		}
+	}
+	
+	sort.Slice(offsets, func(i, j int) bool {
+		return offsets[i] > offsets[j]
+	})
+	return offsets, nil
+}
+
+func (a *App) reminderOffsets(poll models.Poll) []time.Duration {
+	if len(poll.Reminders) > 0 {
+		return poll.Reminders
+	}
+	return a.reminders
+}
+
+func (a *App) reminderMode(poll models.Poll) string {
+	if poll.ReminderMode != "" {
+		return poll.ReminderMode
+	}
+	if a.config.Bot.ReminderMode == models.ReminderModeDM {
+		return models.ReminderModeDM
+	}
+	return models.ReminderModeChannel
+}
+
+// sendDueReminders отправляет напоминания по открытым голосованиям со сроком.
+// Отправленные смещения сохраняются в голосовании, чтобы после перезапуска
+// бота напоминания не дублировались.
+func (a *App) sendDueReminders() {
+	polls, err := a.repository.ListPolls()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list polls for reminders")
+		return
+	}
+	
+	now := time.Now()
+	for _, poll := range polls {
+		if !poll.IsOpen() || poll.ClosesAt.IsZero() || !poll.ClosesAt.After(now) {
+			continue
+		}
+		
+		start := poll.CreatedAt
+		if poll.OpensAt.After(start) {
+			start = poll.OpensAt
+		}
+		
+		var due []time.Duration
+		for _, offset := range a.reminderOffsets(poll) {
+			remindAt := poll.ClosesAt.Add(-offset)
+			// Напоминание, время которого наступило раньше открытия, не имеет смысла
+			if remindAt.After(now) || remindAt.Before(start) || slices.Contains(poll.RemindersSent, offset) {
+				continue
+			}
+			due = append(due, offset)
+		}
+		
+		if len(due) == 0 {
+			continue
+		}
+		
+		// Если пропущено несколько напоминаний, достаточно одного
+		if err := a.sendReminder(poll, poll.ClosesAt.Sub(now)); err != nil {
+			a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to send reminder")
+			continue
+		}
+		
+		poll.RemindersSent = append(poll.RemindersSent, due...)
+		if err := a.repository.SetRemindersSent(poll.ID, poll.RemindersSent); err != nil {
+			a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to save sent reminders")
+		}
+	}
+}
+
+func (a *App) sendReminder(poll models.Poll, remaining time.Duration) error {
+	eligible, err := a.eligibility.EligibleVoters(poll)
+	if err != nil {
+		return err
+	}
+	
+	votes, err := a.repository.GetVotes(poll.ID)
+	if err != nil {
+		return err
+	}
+	
+	voted := make(map[string]bool, len(votes))
+	for _, vote := range votes {
+		voted[vote.UserID] = true
+	}
+	
+	var pending []string
+	for _, userID := range eligible {
+		if !voted[userID] {
+			pending = append(pending, userID)
+		}
+	}
+	
+	if len(pending) == 0 {
+		a.logger.WithField("poll_id", poll.ID).Info("Everyone has voted, skipping reminder")
+		return nil
+	}
+	
+	a.logger.WithField("poll_id", poll.ID).WithField("pending", len(pending)).Info("Sending poll reminder")
+	
+	if a.reminderMode(poll) == models.ReminderModeDM {
+		message := fmt.Sprintf("⏰ Вы ещё не проголосовали в голосовании «%s» в канале ~%s. До завершения осталось %s.\nДля голосования отправьте: `vote %s ...`", poll.Title, a.channelName(poll.ChannelID), formatRemaining(remaining), poll.ID)
+		for _, userID := range pending {
+			if _, err := a.mmClient.SendDirectMessage(userID, message); err != nil {
+				a.logger.WithError(err).WithField("user_id", userID).Warn("Failed to send reminder")
+			}
+		}
+		return nil
+	}
+	
+	message := fmt.Sprintf("⏰ До завершения голосования «%s» (`%s`) осталось %s. Ещё не проголосовали: %d из %d.", poll.Title, poll.ID, formatRemaining(remaining), len(pending), len(eligible))
+	_, err = a.mmClient.CreatePost(poll.ChannelID, message)
+	return err
+}
+
+func (a *App) channelName(channelID string) string {
+	channel, err := a.mmClient.GetChannel(channelID)
+	if err != nil {
+		return channelID
+	}
+	return channel.Name
+}
+
+// formatRemaining округляет оставшееся время до минут.
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "меньше минуты"
+	}
+	
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d д", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d ч", hours))
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%d мин", minutes))
+	}
+	return strings.Join(parts, " ")
+}
\ No newline at end of file
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// Что делать с голосованием, срок хранения которого истёк.
+const (
+	retentionArchive = "archive"
+	retentionDelete  = "delete"
+)
+
+const defaultRetentionInterval = time.Hour
+
+// runRetention периодически убирает из рабочих спейсов голосования,
+// завершённые раньше, чем retention.days дней назад.
+func (a *App) runRetention() {
+	cfg := a.config.Retention
+	if cfg.Days <= 0 {
+		return
+	}
+	if cfg.Action != retentionArchive && cfg.Action != retentionDelete {
+		a.logger.WithField("action", cfg.Action).Error("Unknown retention action, retention disabled")
+		return
+	}
+	
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultRetentionInterval
+	}
+	
+	a.logger.WithField("days", cfg.Days).WithField("action", cfg.Action).Info("Retention policy enabled")
+	
+	a.applyRetention()
+	
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for range ticker.C {
+		a.applyRetention()
+	}
+}
+
+func (a *App) applyRetention() {
+	polls, err := a.repository.ListPolls()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list polls for retention")
+		return
+	}
+	
+	cutoff := time.Now().AddDate(0, 0, -a.config.Retention.Days)
+	for _, poll := range polls {
+		if !expired(poll, cutoff) {
+			continue
+		}
+		
+		var votes int
+		if a.config.Retention.Action == retentionArchive {
+			votes, err = a.repository.ArchivePoll(poll.ID)
+		} else {
+			votes, err = a.repository.PurgePoll(poll.ID)
+		}
+		if err != nil {
+			a.logger.WithError(err).WithField("poll_id", poll.ID).Error("Failed to apply retention")
+			continue
+		}
+		
+		// Журнал голосования удалён или перенесён в архив вместе с ним,
+		// поэтому новая запись в audit осталась бы висячей
+		a.logger.WithField("poll_id", poll.ID).WithField("votes", votes).WithField("action", a.config.Retention.Action).Info("Poll removed by retention policy")
+	}
+}
+
+func expired(poll models.Poll, cutoff time.Time) bool {
+	return poll.IsClosed() && !poll.FinishedAt.IsZero() && poll.FinishedAt.Before(cutoff)
+}
\ No newline at end of file
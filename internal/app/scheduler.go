@@ -0,0 +1,204 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+const scheduleCheckInterval = 30 * time.Second
+
+func (a *App) handleSchedule(userID, channelID string, args []string) error {
+	tokens := tokenize(strings.Join(args, " "))
+	if len(tokens) < 2 || !tokens[0].quoted || !isCreateCommand(tokens[1]) {
+		return a.replyError(channelID, "Ошибка: Используйте: schedule \"0 10 * * MON\" create \"Заголовок\" \"Вариант 1\" \"Вариант 2\" ... [--for 4h]")
+	}
+	
+	spec := tokens[0].text
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Некорректное расписание `%s`. Формат: минуты часы день месяц день_недели, например \"0 10 * * MON\".", spec))
+	}
+	
+	// Аргументы сохраняются с кавычками, чтобы при запуске разобрать их как команду create
+	createArgs := make([]string, 0, len(tokens)-2)
+	for _, token := range tokens[2:] {
+		if token.quoted {
+			createArgs = append(createArgs, `"`+token.text+`"`)
+		} else {
+			createArgs = append(createArgs, token.text)
+		}
+	}
+	
+	parts, flags := parseCommandArgs(createArgs)
+	if len(parts) < 3 {
+		return a.replyError(channelID, "Ошибка: Необходимо указать заголовок и минимум 2 варианта ответа в кавычках.")
+	}
+	
+	// Абсолютное время сработало бы только при первом запуске: каждое
+	// следующее голосование открывалось бы или закрывалось в прошлом
+	if values, ok := flags["opens-at"]; ok {
+		if _, err := parseDuration(strings.Join(values, " ")); err != nil {
+			return a.replyError(channelID, "Ошибка: В расписании --opens-at задаётся только задержкой от запуска, например 2h.")
+		}
+	}
+	if _, ok := flags["closes-at"]; ok {
+		return a.replyError(channelID, "Ошибка: В расписании срок задаётся только длительностью: --for 4h.")
+	}
+	
+	// Флаги проверяются заранее, чтобы ошибка не всплыла только в момент запуска
+	probe := models.Poll{Options: models.NewOptions(parts[1:]), ChannelID: channelID, CreatedAt: time.Now()}
+	if !a.applyCreateFlags(&probe, flags, channelID) {
+		return errCommandFailed
+	}
+	
+	now := time.Now()
+	entry := models.Schedule{
+		ID:        uuid.New().String()[:8],
+		CreatorID: userID,
+		ChannelID: channelID,
+		Spec:      spec,
+		Args:      createArgs,
+		CreatedAt: now,
+		NextRunAt: schedule.Next(now),
+	}
+	
+	err = a.repository.CreateSchedule(entry)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to save schedule")
+		return a.replyError(channelID, "Ошибка при сохранении расписания.")
+	}
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Расписание создано! ID: `%s`. Голосование «%s» будет создаваться по расписанию `%s`, ближайший запуск: %s.", entry.ID, parts[0], spec, entry.NextRunAt.Format("02.01.2006 15:04")))
+	return nil
+}
+
+func (a *App) handleSchedules(channelID string) error {
+	schedules, err := a.repository.ListSchedules()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list schedules")
+		return a.replyError(channelID, "Ошибка при получении списка расписаний.")
+	}
+	
+	message := "### Повторяющиеся голосования в канале\n"
+	count := 0
+	for _, schedule := range schedules {
+		if schedule.ChannelID != channelID {
+			continue
+		}
+		count++
+		
+		title := ""
+		if parts, _ := parseCommandArgs(schedule.Args); len(parts) > 0 {
+			title = parts[0]
+		}
+		message += fmt.Sprintf("- `%s` — «%s», расписание `%s`, ближайший запуск: %s", schedule.ID, title, schedule.Spec, schedule.NextRunAt.Format("02.01.2006 15:04"))
+		if schedule.LastPoll != "" {
+			message += fmt.Sprintf(", последнее голосование: `%s`", schedule.LastPoll)
+		}
+		message += "\n"
+	}
+	
+	if count == 0 {
+		message = "В этом канале нет повторяющихся голосований."
+	}
+	
+	a.mmClient.CreatePost(channelID, message)
+	return nil
+}
+
+func (a *App) handleUnschedule(userID, channelID string, args []string) error {
+	if len(args) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите ID расписания. Используйте: unschedule [ID расписания]")
+	}
+	
+	scheduleID := args[0]
+	
+	schedule, err := a.repository.GetSchedule(scheduleID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Расписание с ID `%s` не найдено.", scheduleID))
+	}
+	
+	if !a.permissions.CanManageSchedule(userID, schedule) {
+		return a.replyError(channelID, "Ошибка: Удалить расписание может только его создатель или администратор.")
+	}
+	
+	err = a.repository.DeleteSchedule(scheduleID)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to delete schedule")
+		return a.replyError(channelID, "Ошибка при удалении расписания.")
+	}
+	
+	a.mmClient.CreatePost(channelID, fmt.Sprintf("Расписание `%s` удалено. Уже созданные голосования не затронуты.", scheduleID))
+	return nil
+}
+
+func (a *App) runSchedules() {
+	ticker := time.NewTicker(scheduleCheckInterval)
+	defer ticker.Stop()
+	
+	for range ticker.C {
+		a.startDueSchedules()
+	}
+}
+
+func (a *App) startDueSchedules() {
+	schedules, err := a.repository.ListSchedules()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list schedules")
+		return
+	}
+	
+	now := time.Now()
+	for _, schedule := range schedules {
+		if schedule.NextRunAt.After(now) {
+			continue
+		}
+		
+		spec, err := cron.ParseStandard(schedule.Spec)
+		if err != nil {
+			a.logger.WithError(err).WithField("schedule_id", schedule.ID).Error("Invalid schedule spec")
+			continue
+		}
+		
+		// Следующий запуск считается от текущего момента, чтобы после простоя
+		// бота не создавать пропущенные голосования пачкой
+		next := spec.Next(now)
+		
+		args := schedule.Args
+		if _, flags := parseCommandArgs(args); flags["for"] == nil {
+			// Без явного срока голосование закрывается к следующему запуску
+			minutes := int(next.Sub(now).Minutes())
+			if minutes < 1 {
+				minutes = 1
+			}
+			args = append(append([]string(nil), args...), "--for", fmt.Sprintf("%dm", minutes))
+		}
+		
+		a.logger.WithField("schedule_id", schedule.ID).Info("Starting scheduled poll")
+		
+		if poll, ok := a.createPoll(schedule.CreatorID, schedule.ChannelID, args); ok {
+			schedule.LastPoll = poll.ID
+			a.recordAudit(poll.ID, schedule.CreatorID, "scheduled_create", fmt.Sprintf("schedule %s", schedule.ID))
+		}
+		
+		schedule.LastRunAt = now
+		schedule.NextRunAt = next
+		
+		if err := a.repository.UpdateSchedule(schedule); err != nil {
+			a.logger.WithError(err).WithField("schedule_id", schedule.ID).Error("Failed to update schedule")
+		}
+	}
+}
+
+func isCreateCommand(token argToken) bool {
+	switch strings.ToLower(token.text) {
+	case "create", "new", "poll":
+		return !token.quoted
+	}
+	return false
+}
\ No newline at end of file
@@ -0,0 +1,279 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// mostActiveLimit — сколько самых активных участников показывать в сводке по каналу.
+const mostActiveLimit = 5
+
+type voteChanges struct {
+	Changes int
+	Voters  int
+}
+
+type voterActivity struct {
+	UserID string
+	Polls  int
+}
+
+func (a *App) handleStats(channelID string, args []string) error {
+	if len(args) < 1 {
+		return a.replyError(channelID, "Ошибка: Укажите ID голосования или channel. Используйте: stats [ID голосования] или stats channel")
+	}
+	
+	if args[0] == "channel" {
+		return a.handleChannelStats(channelID)
+	}
+	
+	pollID := args[0]
+	
+	poll, err := a.repository.GetPoll(pollID)
+	if err != nil {
+		return a.replyError(channelID, fmt.Sprintf("Ошибка: Голосование с ID `%s` не найдено.", pollID))
+	}
+	
+	votes, err := a.repository.GetVotes(pollID)
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to get votes")
+		return a.replyError(channelID, "Ошибка при получении голосов.")
+	}
+	
+	entries, err := a.repository.GetAuditEntries(pollID)
+	if err != nil {
+		a.logger.WithError(err).WithField("poll_id", pollID).Warn("Failed to get audit entries")
+	}
+	
+	message := fmt.Sprintf("### Статистика голосования «%s»\n", poll.Title)
+	message += fmt.Sprintf("**ID**: `%s`%s\n", poll.ID, formatStatus(poll))
+	
+	// Явка считается так же, как в сводке по каналу: от числа участников
+	// голосования, а если оно не задано — от состава канала
+	electorate := poll.Electorate
+	if electorate == 0 {
+		electorate, err = a.channelMembers(poll.ChannelID)
+		if err != nil {
+			a.logger.WithError(err).WithField("poll_id", pollID).Warn("Failed to count channel members")
+		}
+	}
+	message += fmt.Sprintf("\n**Проголосовали**: %d", len(votes))
+	if electorate > 0 {
+		message += fmt.Sprintf(" из %d участников (%.1f%%)", electorate, percent(len(votes), electorate))
+	}
+	
+	if first, ok := firstVoteAt(entries); ok {
+		message += fmt.Sprintf("\n**Первый голос**: через %s после открытия", formatRemaining(first.Sub(pollStart(poll))))
+	}
+	
+	changes := countVoteChanges(entries)
+	message += fmt.Sprintf("\n**Изменения голоса**: %d", changes.Changes)
+	if changes.Changes > 0 {
+		message += fmt.Sprintf(" (участников: %d)", changes.Voters)
+	}
+	
+	buckets := hourlyBuckets(entries)
+	if len(buckets) > 0 {
+		message += "\n\n**Бюллетени по часам** (с учётом повторных):\n"
+		for _, hour := range sortedHours(buckets) {
+			message += fmt.Sprintf("- %s — %d\n", hour.Format("02.01.2006 15:00"), buckets[hour])
+		}
+	}
+	
+	a.mmClient.CreatePost(channelID, message)
+	return nil
+}
+
+func (a *App) handleChannelStats(channelID string) error {
+	polls, err := a.repository.ListPolls()
+	if err != nil {
+		a.logger.WithError(err).Error("Failed to list polls")
+		return a.replyError(channelID, "Ошибка при получении списка голосований.")
+	}
+	
+	members, err := a.channelMembers(channelID)
+	if err != nil {
+		a.logger.WithError(err).WithField("channel_id", channelID).Warn("Failed to count channel members")
+	}
+	
+	var (
+		run, closed int
+		turnouts    []float64
+		activity    = make(map[string]int)
+	)
+	for _, poll := range polls {
+		if poll.ChannelID != channelID {
+			continue
+		}
+		run++
+		if poll.IsClosed() {
+			closed++
+		}
+		
+		votes, err := a.repository.GetVotes(poll.ID)
+		if err != nil {
+			a.logger.WithError(err).WithField("poll_id", poll.ID).Warn("Failed to get votes")
+			continue
+		}
+		
+		electorate := poll.Electorate
+		if electorate == 0 {
+			electorate = members
+		}
+		if electorate > 0 && !poll.IsScheduled() {
+			turnouts = append(turnouts, percent(len(votes), electorate))
+		}
+		
+		if poll.Anonymous {
+			continue
+		}
+		for _, vote := range votes {
+			activity[vote.UserID]++
+		}
+	}
+	
+	if run == 0 {
+		a.mmClient.CreatePost(channelID, "В этом канале ещё не проводилось голосований.")
+		return nil
+	}
+	
+	message := "### Статистика голосований канала\n"
+	message += fmt.Sprintf("**Голосований**: %d (завершено: %d)\n", run, closed)
+	if len(turnouts) > 0 {
+		message += fmt.Sprintf("**Средняя явка**: %.1f%%\n", average(turnouts))
+	}
+	
+	active := mostActive(activity, mostActiveLimit)
+	if len(active) > 0 {
+		message += "\n**Самые активные участники**:\n"
+		for i, voter := range active {
+			name := voter.UserID
+			if user, err := a.mmClient.GetUser(voter.UserID); err == nil {
+				name = "@" + user.Username
+			}
+			message += fmt.Sprintf("%d. %s — %d\n", i+1, name, voter.Polls)
+		}
+		message += "\n_Анонимные голосования в рейтинге не учитываются._"
+	}
+	
+	a.mmClient.CreatePost(channelID, strings.TrimSuffix(message, "\n"))
+	return nil
+}
+
+// channelMembers возвращает число участников канала, не считая бота.
+func (a *App) channelMembers(channelID string) (int, error) {
+	memberIDs, err := a.mmClient.GetChannelMemberIDs(channelID)
+	if err != nil {
+		return 0, err
+	}
+	
+	count := 0
+	for _, userID := range memberIDs {
+		if userID != a.mmClient.GetBotUserID() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// pollStart — момент, с которого голосование принимает голоса.
+func pollStart(poll models.Poll) time.Time {
+	if !poll.OpensAt.IsZero() {
+		return poll.OpensAt
+	}
+	return poll.CreatedAt
+}
+
+// firstVoteAt берёт время первого бюллетеня из журнала аудита: повторный
+// голос переписывает время в самом голосе.
+func firstVoteAt(entries []models.AuditEntry) (time.Time, bool) {
+	var first time.Time
+	for _, entry := range entries {
+		if entry.Action == "vote" && (first.IsZero() || entry.CreatedAt.Before(first)) {
+			first = entry.CreatedAt
+		}
+	}
+	return first, !first.IsZero()
+}
+
+// countVoteChanges считает по журналу аудита повторные бюллетени и отзывы голоса.
+func countVoteChanges(entries []models.AuditEntry) voteChanges {
+	ballots := make(map[string]int)
+	changed := make(map[string]bool)
+	
+	var result voteChanges
+	for _, entry := range entries {
+		switch entry.Action {
+		case "vote":
+			ballots[entry.UserID]++
+			if ballots[entry.UserID] > 1 {
+				result.Changes++
+				changed[entry.UserID] = true
+			}
+		case "unvote":
+			result.Changes++
+			changed[entry.UserID] = true
+		}
+	}
+	
+	result.Voters = len(changed)
+	return result
+}
+
+// hourlyBuckets распределяет по часам бюллетени из журнала аудита: повторный
+// голос заменяет бюллетень и его время, поэтому сами голоса дали бы
+// искажённую картину, расходящуюся с числом изменений.
+func hourlyBuckets(entries []models.AuditEntry) map[time.Time]int {
+	buckets := make(map[time.Time]int)
+	for _, entry := range entries {
+		if entry.Action == "vote" {
+			buckets[entry.CreatedAt.Truncate(time.Hour)]++
+		}
+	}
+	return buckets
+}
+
+func sortedHours(buckets map[time.Time]int) []time.Time {
+	hours := make([]time.Time, 0, len(buckets))
+	for hour := range buckets {
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool {
+		return hours[i].Before(hours[j])
+	})
+	return hours
+}
+
+func mostActive(activity map[string]int, limit int) []voterActivity {
+	voters := make([]voterActivity, 0, len(activity))
+	for userID, polls := range activity {
+		voters = append(voters, voterActivity{UserID: userID, Polls: polls})
+	}
+	sort.Slice(voters, func(i, j int) bool {
+		if voters[i].Polls != voters[j].Polls {
+			return voters[i].Polls > voters[j].Polls
+		}
+		return voters[i].UserID < voters[j].UserID
+	})
+	
+	if len(voters) > limit {
+		voters = voters[:limit]
+	}
+	return voters
+}
+
+func percent(part, total int) float64 {
+	return float64(part) / float64(total) * 100
+}
+
+func average(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
\ No newline at end of file
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/dew-77/mattermost-vote-system/internal/config"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+const (
+	webhookQueueSize  = 1000
+	webhookWorkers    = 4
+	webhookMaxBackoff = 5 * time.Minute
+)
+
+type webhookDelivery struct {
+	endpoint  config.WebhookEndpoint
+	eventType string
+	payload   []byte
+}
+
+// WebhookDispatcher отправляет события на настроенные URL. Тело подписывается
+// HMAC-SHA256 с секретом получателя; неудачные доставки повторяются с
+// экспоненциальной задержкой, а после последней попытки сохраняются в
+// очередь недоставленных сообщений в Tarantool.
+type WebhookDispatcher struct {
+	config     config.WebhooksConfig
+	repository repository.PollRepository
+	logger     *logrus.Logger
+	client     *http.Client
+	queue      chan webhookDelivery
+}
+
+func NewWebhookDispatcher(cfg config.WebhooksConfig, repo repository.PollRepository, logger *logrus.Logger) *WebhookDispatcher {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	
+	return &WebhookDispatcher{
+		config:     cfg,
+		repository: repo,
+		logger:     logger,
+		client:     &http.Client{Timeout: cfg.Timeout},
+		queue:      make(chan webhookDelivery, webhookQueueSize),
+	}
+}
+
+// Enqueue ставит событие в очередь для каждого подписанного получателя.
+// Подходит как обработчик EventBus: не блокируется, при переполнении
+// очереди событие сразу уходит в недоставленные.
+func (d *WebhookDispatcher) Enqueue(event models.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		d.logger.WithError(err).WithField("event", event.Type).Error("Failed to encode webhook payload")
+		return
+	}
+	
+	for _, endpoint := range d.config.Endpoints {
+		if !subscribed(endpoint, event.Type) {
+			continue
+		}
+		
+		delivery := webhookDelivery{endpoint: endpoint, eventType: event.Type, payload: payload}
+		select {
+		case d.queue <- delivery:
+			monitoring.EventQueueDepth.Set(float64(len(d.queue)))
+		default:
+			d.logger.WithField("url", endpoint.URL).Warn("Webhook queue is full")
+			d.deadLetter(delivery, 0, fmt.Errorf("queue is full"))
+		}
+	}
+}
+
+// Start запускает обработчики очереди. Их несколько, чтобы повторные
+// попытки к недоступному получателю не задерживали остальные доставки.
+func (d *WebhookDispatcher) Start() {
+	for i := 0; i < webhookWorkers; i++ {
+		go func() {
+			for delivery := range d.queue {
+				monitoring.EventQueueDepth.Set(float64(len(d.queue)))
+				d.deliver(delivery)
+			}
+		}()
+	}
+}
+
+func (d *WebhookDispatcher) deliver(delivery webhookDelivery) {
+	backoff := d.config.InitialBackoff
+	
+	var err error
+	for attempt := 1; attempt <= d.config.MaxAttempts; attempt++ {
+		err = d.send(delivery)
+		if err == nil {
+			return
+		}
+		
+		d.logger.WithError(err).WithFields(logrus.Fields{
+			"url":     delivery.endpoint.URL,
+			"event":   delivery.eventType,
+			"attempt": attempt,
+		}).Warn("Webhook delivery failed")
+		
+		if attempt == d.config.MaxAttempts {
+			break
+		}
+		
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > webhookMaxBackoff {
+			backoff = webhookMaxBackoff
+		}
+	}
+	
+	d.deadLetter(delivery, d.config.MaxAttempts, err)
+}
+
+func (d *WebhookDispatcher) send(delivery webhookDelivery) error {
+	req, err := http.NewRequest(http.MethodPost, delivery.endpoint.URL, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return err
+	}
+	
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vote-Event", delivery.eventType)
+	if delivery.endpoint.Secret != "" {
+		req.Header.Set("X-Vote-Signature", "sha256="+signPayload(delivery.endpoint.Secret, delivery.payload))
+	}
+	
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) deadLetter(delivery webhookDelivery, attempts int, cause error) {
+	letter := models.DeadLetter{
+		ID:        uuid.New().String(),
+		URL:       delivery.endpoint.URL,
+		EventType: delivery.eventType,
+		Payload:   string(delivery.payload),
+		Attempts:  attempts,
+		LastError: cause.Error(),
+		FailedAt:  time.Now(),
+	}
+	
+	if err := d.repository.AddDeadLetter(letter); err != nil {
+		d.logger.WithError(err).WithField("url", delivery.endpoint.URL).Error("Failed to save undelivered webhook")
+	}
+}
+
+// signPayload возвращает HMAC-SHA256 тела запроса в шестнадцатеричном виде.
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func subscribed(endpoint config.WebhookEndpoint, eventType string) bool {
+	if len(endpoint.Events) == 0 {
+		return true
+	}
+	for _, event := range endpoint.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
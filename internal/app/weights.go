@@ -0,0 +1,49 @@
+package app
+
+import (
+	"strings"
+)
+
+// groupWeight возвращает вес голоса пользователя по таблице групп из конфигурации.
+// Если пользователь состоит в нескольких группах, берётся наибольший вес;
+// 0 означает, что вес по группам не задан и голос считается с весом 1.
+func (a *App) groupWeight(userID string) int {
+	best := 0
+	for name, weight := range a.config.Bot.GroupWeights {
+		if weight <= best {
+			continue
+		}
+		
+		groupID, err := a.groupID(name)
+		if err != nil {
+			a.logger.WithError(err).WithField("group", name).Warn("Failed to resolve weighted group")
+			continue
+		}
+		
+		members, err := a.eligibility.groupMemberSet(groupID)
+		if err != nil {
+			a.logger.WithError(err).WithField("group", name).Warn("Failed to get weighted group members")
+			continue
+		}
+		
+		if members[userID] {
+			best = weight
+		}
+	}
+	return best
+}
+
+func (a *App) groupID(name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "@"))
+	if groupID, ok := a.groupIDs.get(name); ok {
+		return groupID, nil
+	}
+	
+	group, err := a.mmClient.GetGroupByName(name)
+	if err != nil {
+		return "", err
+	}
+	
+	a.groupIDs.set(name, group.Id)
+	return group.Id, nil
+}
\ No newline at end of file
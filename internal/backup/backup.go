@@ -0,0 +1,422 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+// Формат архива: gzip-сжатый JSONL. Первая строка — заголовок с версией,
+// далее для каждого голосования идёт запись poll, затем его голоса, веса
+// и журнал действий. За ними следуют голосования из архива хранилища —
+// каждое одной записью archive — и расписания. Неотправленные события
+// вебхуков (dead_letters) в резервную копию не входят.
+//
+// Версия 2 добавила записи audit, archive и schedule; архивы версии 1
+// по-прежнему загружаются.
+const (
+	Format  = "vote-bot-backup"
+	Version = 2
+)
+
+// Политики разрешения конфликтов при восстановлении: что делать, если
+// голосование или расписание с таким ID уже есть в хранилище.
+const (
+	OnConflictSkip      = "skip"
+	OnConflictOverwrite = "overwrite"
+)
+
+const (
+	recordPoll     = "poll"
+	recordVote     = "vote"
+	recordWeight   = "weight"
+	recordAudit    = "audit"
+	recordArchive  = "archive"
+	recordSchedule = "schedule"
+)
+
+// maxLineSize ограничивает длину строки архива: голосование с длинными
+// списками участников или архивное голосование со всеми голосами может
+// занимать мегабайты.
+const maxLineSize = 64 * 1024 * 1024
+
+type Header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type record struct {
+	Type     string             `json:"type"`
+	Poll     *models.Poll       `json:"poll,omitempty"`
+	Vote     *models.Vote       `json:"vote,omitempty"`
+	Weight   *weight            `json:"weight,omitempty"`
+	Audit    *models.AuditEntry `json:"audit,omitempty"`
+	Archive  *archivedPoll      `json:"archive,omitempty"`
+	Schedule *models.Schedule   `json:"schedule,omitempty"`
+}
+
+type weight struct {
+	PollID string `json:"poll_id"`
+	UserID string `json:"user_id"`
+	Weight int    `json:"weight"`
+}
+
+type archivedPoll struct {
+	ArchivedAt time.Time           `json:"archived_at"`
+	Poll       models.Poll         `json:"poll"`
+	Votes      []models.Vote       `json:"votes,omitempty"`
+	Weights    []weight            `json:"weights,omitempty"`
+	Audit      []models.AuditEntry `json:"audit,omitempty"`
+}
+
+// Stats — сколько записей выгружено или восстановлено.
+type Stats struct {
+	Polls            int `json:"polls"`
+	ArchivedPolls    int `json:"archived_polls"`
+	Votes            int `json:"votes"`
+	Weights          int `json:"weights"`
+	Audit            int `json:"audit"`
+	Schedules        int `json:"schedules"`
+	SkippedPolls     int `json:"skipped_polls,omitempty"`
+	SkippedSchedules int `json:"skipped_schedules,omitempty"`
+}
+
+func (s *Stats) count(snapshot repository.PollSnapshot) {
+	if snapshot.ArchivedAt.IsZero() {
+		s.Polls++
+	} else {
+		s.ArchivedPolls++
+	}
+	s.Votes += len(snapshot.Votes)
+	s.Weights += len(snapshot.Weights)
+	s.Audit += len(snapshot.Audit)
+}
+
+// Write выгружает все голосования с голосами, весами и журналом, архив
+// голосований и расписания. Голосования пишутся по мере чтения, поэтому
+// весь архив в памяти не держится.
+func Write(repo repository.PollRepository, w io.Writer) (Stats, error) {
+	var stats Stats
+	
+	polls, err := repo.ListPolls()
+	if err != nil {
+		return stats, err
+	}
+	
+	zw := gzip.NewWriter(w)
+	encoder := json.NewEncoder(zw)
+	
+	if err := encoder.Encode(Header{Format: Format, Version: Version, CreatedAt: time.Now()}); err != nil {
+		return stats, fmt.Errorf("failed to write backup header: %w", err)
+	}
+	
+	for i := range polls {
+		poll := polls[i]
+		if err := encoder.Encode(record{Type: recordPoll, Poll: &poll}); err != nil {
+			return stats, fmt.Errorf("failed to write poll %s: %w", poll.ID, err)
+		}
+		stats.Polls++
+		
+		votes, err := repo.GetVotes(poll.ID)
+		if err != nil {
+			return stats, err
+		}
+		for j := range votes {
+			if err := encoder.Encode(record{Type: recordVote, Vote: &votes[j]}); err != nil {
+				return stats, fmt.Errorf("failed to write vote: %w", err)
+			}
+			stats.Votes++
+		}
+		
+		weights, err := repo.GetWeights(poll.ID)
+		if err != nil {
+			return stats, err
+		}
+		for userID, value := range weights {
+			if err := encoder.Encode(record{Type: recordWeight, Weight: &weight{PollID: poll.ID, UserID: userID, Weight: value}}); err != nil {
+				return stats, fmt.Errorf("failed to write weight: %w", err)
+			}
+			stats.Weights++
+		}
+		
+		entries, err := repo.GetAuditEntries(poll.ID)
+		if err != nil {
+			return stats, err
+		}
+		for j := range entries {
+			if err := encoder.Encode(record{Type: recordAudit, Audit: &entries[j]}); err != nil {
+				return stats, fmt.Errorf("failed to write audit entry: %w", err)
+			}
+			stats.Audit++
+		}
+	}
+	
+	archived, err := repo.ListArchivedPolls()
+	if err != nil {
+		return stats, err
+	}
+	for _, snapshot := range archived {
+		archive := archivedPoll{
+			ArchivedAt: snapshot.ArchivedAt,
+			Poll:       snapshot.Poll,
+			Votes:      snapshot.Votes,
+			Audit:      snapshot.Audit,
+		}
+		for userID, value := range snapshot.Weights {
+			archive.Weights = append(archive.Weights, weight{PollID: snapshot.Poll.ID, UserID: userID, Weight: value})
+		}
+		if err := encoder.Encode(record{Type: recordArchive, Archive: &archive}); err != nil {
+			return stats, fmt.Errorf("failed to write archived poll %s: %w", snapshot.Poll.ID, err)
+		}
+		stats.count(snapshot)
+	}
+	
+	schedules, err := repo.ListSchedules()
+	if err != nil {
+		return stats, err
+	}
+	for i := range schedules {
+		if err := encoder.Encode(record{Type: recordSchedule, Schedule: &schedules[i]}); err != nil {
+			return stats, fmt.Errorf("failed to write schedule %s: %w", schedules[i].ID, err)
+		}
+		stats.Schedules++
+	}
+	
+	if err := zw.Close(); err != nil {
+		return stats, fmt.Errorf("failed to finish backup: %w", err)
+	}
+	return stats, nil
+}
+
+// Restore загружает архив в хранилище. Голосования и расписания, которые
+// уже есть, пропускаются (skip) или заменяются (overwrite); голосование
+// заменяется целиком одной операцией хранилища вместе с голосами, весами
+// и журналом. С dryRun архив только читается и проверяется.
+func Restore(repo repository.PollRepository, r io.Reader, onConflict string, dryRun bool) (Stats, error) {
+	var stats Stats
+	
+	if onConflict != OnConflictSkip && onConflict != OnConflictOverwrite {
+		return stats, fmt.Errorf("unknown conflict policy %q", onConflict)
+	}
+	
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return stats, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer zr.Close()
+	
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	
+	if !scanner.Scan() {
+		return stats, fmt.Errorf("failed to read backup header: %w", scanErr(scanner))
+	}
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return stats, fmt.Errorf("failed to decode backup header: %w", err)
+	}
+	if header.Format != Format {
+		return stats, fmt.Errorf("not a vote bot backup: format %q", header.Format)
+	}
+	if header.Version < 1 || header.Version > Version {
+		return stats, fmt.Errorf("unsupported backup version %d (supported up to %d)", header.Version, Version)
+	}
+	
+	restorer := &restorer{
+		repo:       repo,
+		onConflict: onConflict,
+		dryRun:     dryRun,
+		stats:      &stats,
+	}
+	
+	for line := 2; scanner.Scan(); line++ {
+		var rec record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return stats, fmt.Errorf("line %d: failed to decode record: %w", line, err)
+		}
+		if err := restorer.add(rec); err != nil {
+			return stats, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, fmt.Errorf("failed to read backup: %w", err)
+	}
+	
+	if err := restorer.flush(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// restorer собирает голосование из записи poll и следующих за ней записей
+// его голосов, весов и журнала и сохраняет его целиком, когда блок закончился.
+type restorer struct {
+	repo       repository.PollRepository
+	onConflict string
+	dryRun     bool
+	stats      *Stats
+	pending    *repository.PollSnapshot
+	archived   map[string]bool
+	schedules  map[string]bool
+}
+
+func (r *restorer) add(rec record) error {
+	switch {
+	case rec.Type == recordPoll && rec.Poll != nil:
+		if err := r.flush(); err != nil {
+			return err
+		}
+		r.pending = &repository.PollSnapshot{Poll: *rec.Poll, Weights: make(map[string]int)}
+	case rec.Type == recordVote && rec.Vote != nil:
+		if err := r.expect(rec.Type, rec.Vote.PollID); err != nil {
+			return err
+		}
+		r.pending.Votes = append(r.pending.Votes, *rec.Vote)
+	case rec.Type == recordWeight && rec.Weight != nil:
+		if err := r.expect(rec.Type, rec.Weight.PollID); err != nil {
+			return err
+		}
+		r.pending.Weights[rec.Weight.UserID] = rec.Weight.Weight
+	case rec.Type == recordAudit && rec.Audit != nil:
+		if err := r.expect(rec.Type, rec.Audit.PollID); err != nil {
+			return err
+		}
+		r.pending.Audit = append(r.pending.Audit, *rec.Audit)
+	case rec.Type == recordArchive && rec.Archive != nil:
+		if err := r.flush(); err != nil {
+			return err
+		}
+		snapshot := repository.PollSnapshot{
+			Poll:       rec.Archive.Poll,
+			Votes:      rec.Archive.Votes,
+			Weights:    make(map[string]int, len(rec.Archive.Weights)),
+			Audit:      rec.Archive.Audit,
+			ArchivedAt: rec.Archive.ArchivedAt,
+		}
+		for _, w := range rec.Archive.Weights {
+			snapshot.Weights[w.UserID] = w.Weight
+		}
+		return r.restorePoll(snapshot)
+	case rec.Type == recordSchedule && rec.Schedule != nil:
+		if err := r.flush(); err != nil {
+			return err
+		}
+		return r.restoreSchedule(*rec.Schedule)
+	default:
+		return fmt.Errorf("unknown record type %q", rec.Type)
+	}
+	return nil
+}
+
+// expect проверяет, что запись относится к голосованию, блок которого
+// сейчас читается: записи пишутся сразу после своего голосования.
+func (r *restorer) expect(recordType, pollID string) error {
+	if r.pending == nil || r.pending.Poll.ID != pollID {
+		return fmt.Errorf("%s record for poll %s is outside its poll block", recordType, pollID)
+	}
+	return nil
+}
+
+func (r *restorer) flush() error {
+	if r.pending == nil {
+		return nil
+	}
+	snapshot := *r.pending
+	r.pending = nil
+	return r.restorePoll(snapshot)
+}
+
+func (r *restorer) restorePoll(snapshot repository.PollSnapshot) error {
+	exists, err := r.pollExists(snapshot.Poll.ID)
+	if err != nil {
+		return err
+	}
+	if exists && r.onConflict == OnConflictSkip {
+		r.stats.SkippedPolls++
+		return nil
+	}
+	
+	if !r.dryRun {
+		if err := r.repo.RestorePoll(snapshot); err != nil {
+			return fmt.Errorf("poll %s: %w", snapshot.Poll.ID, err)
+		}
+	}
+	if !snapshot.ArchivedAt.IsZero() && r.archived != nil {
+		r.archived[snapshot.Poll.ID] = true
+	}
+	r.stats.count(snapshot)
+	return nil
+}
+
+// pollExists ищет голосование среди рабочих, а затем в архиве хранилища.
+// ID архивных голосований читаются один раз за восстановление.
+func (r *restorer) pollExists(pollID string) (bool, error) {
+	_, err := r.repo.GetPoll(pollID)
+	switch {
+	case err == nil:
+		return true, nil
+	case !errors.Is(err, repository.ErrPollNotFound):
+		return false, err
+	}
+	
+	if r.archived == nil {
+		snapshots, err := r.repo.ListArchivedPolls()
+		if err != nil {
+			return false, err
+		}
+		r.archived = make(map[string]bool, len(snapshots))
+		for _, snapshot := range snapshots {
+			r.archived[snapshot.Poll.ID] = true
+		}
+	}
+	return r.archived[pollID], nil
+}
+
+func (r *restorer) restoreSchedule(schedule models.Schedule) error {
+	if r.schedules == nil {
+		existing, err := r.repo.ListSchedules()
+		if err != nil {
+			return err
+		}
+		r.schedules = make(map[string]bool, len(existing))
+		for _, s := range existing {
+			r.schedules[s.ID] = true
+		}
+	}
+	
+	exists := r.schedules[schedule.ID]
+	if exists && r.onConflict == OnConflictSkip {
+		r.stats.SkippedSchedules++
+		return nil
+	}
+	
+	if !r.dryRun {
+		var err error
+		if exists {
+			err = r.repo.UpdateSchedule(schedule)
+		} else {
+			err = r.repo.CreateSchedule(schedule)
+		}
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", schedule.ID, err)
+		}
+	}
+	r.schedules[schedule.ID] = true
+	r.stats.Schedules++
+	return nil
+}
+
+func scanErr(scanner *bufio.Scanner) error {
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
\ No newline at end of file
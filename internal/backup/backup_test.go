@@ -0,0 +1,308 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+// fakeRepository хранит рабочие и архивные голосования и расписания в памяти.
+// Методы, которые резервное копирование не вызывает, остаются от встроенного
+// nil-интерфейса и паникуют.
+type fakeRepository struct {
+	repository.PollRepository
+	polls     map[string]repository.PollSnapshot
+	archive   map[string]repository.PollSnapshot
+	schedules map[string]models.Schedule
+	restored  []string
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		polls:     make(map[string]repository.PollSnapshot),
+		archive:   make(map[string]repository.PollSnapshot),
+		schedules: make(map[string]models.Schedule),
+	}
+}
+
+func (f *fakeRepository) GetPoll(pollID string) (models.Poll, error) {
+	snapshot, ok := f.polls[pollID]
+	if !ok {
+		return models.Poll{}, repository.ErrPollNotFound
+	}
+	return snapshot.Poll, nil
+}
+
+func (f *fakeRepository) ListPolls() ([]models.Poll, error) {
+	var polls []models.Poll
+	for _, snapshot := range f.polls {
+		polls = append(polls, snapshot.Poll)
+	}
+	return polls, nil
+}
+
+func (f *fakeRepository) GetVotes(pollID string) ([]models.Vote, error) {
+	return f.polls[pollID].Votes, nil
+}
+
+func (f *fakeRepository) GetWeights(pollID string) (map[string]int, error) {
+	return f.polls[pollID].Weights, nil
+}
+
+func (f *fakeRepository) GetAuditEntries(pollID string) ([]models.AuditEntry, error) {
+	return f.polls[pollID].Audit, nil
+}
+
+func (f *fakeRepository) ListArchivedPolls() ([]repository.PollSnapshot, error) {
+	var snapshots []repository.PollSnapshot
+	for _, snapshot := range f.archive {
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (f *fakeRepository) RestorePoll(snapshot repository.PollSnapshot) error {
+	f.restored = append(f.restored, snapshot.Poll.ID)
+	if snapshot.ArchivedAt.IsZero() {
+		delete(f.archive, snapshot.Poll.ID)
+		f.polls[snapshot.Poll.ID] = snapshot
+	} else {
+		delete(f.polls, snapshot.Poll.ID)
+		f.archive[snapshot.Poll.ID] = snapshot
+	}
+	return nil
+}
+
+func (f *fakeRepository) ListSchedules() ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	for _, schedule := range f.schedules {
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func (f *fakeRepository) CreateSchedule(schedule models.Schedule) error {
+	f.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (f *fakeRepository) UpdateSchedule(schedule models.Schedule) error {
+	f.schedules[schedule.ID] = schedule
+	return nil
+}
+
+var testTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// sourceRepository — хранилище с рабочим голосованием, архивным голосованием
+// и расписанием.
+func sourceRepository() *fakeRepository {
+	repo := newFakeRepository()
+	repo.polls["live1"] = repository.PollSnapshot{
+		Poll: models.Poll{
+			ID:        "live1",
+			Title:     "Обед",
+			ChannelID: "c1",
+			Status:    models.PollStatusOpen,
+			Options:   models.NewOptions([]string{"Пицца", "Суши"}),
+			CreatedAt: testTime,
+		},
+		Votes: []models.Vote{
+			{PollID: "live1", UserID: "u1", OptionID: 0, VotedAt: testTime.Add(time.Minute)},
+			{PollID: "live1", UserID: "u2", OptionID: 1, VotedAt: testTime.Add(2 * time.Minute)},
+		},
+		Weights: map[string]int{"u1": 3},
+		Audit: []models.AuditEntry{
+			{ID: "a1", PollID: "live1", UserID: "u1", Action: "vote", CreatedAt: testTime.Add(time.Minute)},
+		},
+	}
+	repo.archive["old1"] = repository.PollSnapshot{
+		Poll: models.Poll{
+			ID:         "old1",
+			Title:      "Ретро",
+			ChannelID:  "c1",
+			Status:     models.PollStatusClosed,
+			Options:    models.NewOptions([]string{"Да", "Нет"}),
+			CreatedAt:  testTime.AddDate(0, -1, 0),
+			FinishedAt: testTime.AddDate(0, -1, 1),
+		},
+		Votes: []models.Vote{
+			{PollID: "old1", UserID: "u1", OptionID: 1, VotedAt: testTime.AddDate(0, -1, 0)},
+		},
+		Weights: map[string]int{"u1": 2},
+		Audit: []models.AuditEntry{
+			{ID: "a2", PollID: "old1", UserID: "u1", Action: "finish", CreatedAt: testTime.AddDate(0, -1, 1)},
+		},
+		ArchivedAt: testTime,
+	}
+	repo.schedules["s1"] = models.Schedule{
+		ID:        "s1",
+		CreatorID: "u1",
+		ChannelID: "c1",
+		Spec:      "0 10 * * MON",
+		Args:      []string{`"Стендап"`, `"Да"`, `"Нет"`},
+		CreatedAt: testTime,
+		NextRunAt: testTime.AddDate(0, 0, 3),
+	}
+	return repo
+}
+
+func writeBackup(t *testing.T, repo *fakeRepository) []byte {
+	t.Helper()
+	
+	var buf bytes.Buffer
+	stats, err := Write(repo, &buf)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	
+	want := Stats{Polls: 1, ArchivedPolls: 1, Votes: 3, Weights: 2, Audit: 2, Schedules: 1}
+	if stats != want {
+		t.Fatalf("Write() stats = %+v, want %+v", stats, want)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	source := sourceRepository()
+	data := writeBackup(t, source)
+	
+	target := newFakeRepository()
+	stats, err := Restore(target, bytes.NewReader(data), OnConflictSkip, false)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	
+	want := Stats{Polls: 1, ArchivedPolls: 1, Votes: 3, Weights: 2, Audit: 2, Schedules: 1}
+	if stats != want {
+		t.Errorf("Restore() stats = %+v, want %+v", stats, want)
+	}
+	if !reflect.DeepEqual(target.polls, source.polls) {
+		t.Errorf("restored polls = %+v, want %+v", target.polls, source.polls)
+	}
+	if !reflect.DeepEqual(target.archive, source.archive) {
+		t.Errorf("restored archive = %+v, want %+v", target.archive, source.archive)
+	}
+	if !reflect.DeepEqual(target.schedules, source.schedules) {
+		t.Errorf("restored schedules = %+v, want %+v", target.schedules, source.schedules)
+	}
+}
+
+// Голосование из архива хранилища тоже считается существующим, хотя GetPoll
+// его не находит.
+func TestRestoreConflicts(t *testing.T) {
+	data := writeBackup(t, sourceRepository())
+	
+	existing := func() *fakeRepository {
+		repo := sourceRepository()
+		live := repo.polls["live1"]
+		live.Poll.Title = "Ужин"
+		live.Votes = nil
+		repo.polls["live1"] = live
+		archived := repo.archive["old1"]
+		archived.Poll.Title = "Планирование"
+		repo.archive["old1"] = archived
+		schedule := repo.schedules["s1"]
+		schedule.Spec = "0 9 * * FRI"
+		repo.schedules["s1"] = schedule
+		return repo
+	}
+	
+	tests := []struct {
+		name       string
+		onConflict string
+		stats      Stats
+		restored   []string
+		liveTitle  string
+		oldTitle   string
+		spec       string
+	}{
+		{
+			name:       "skip",
+			onConflict: OnConflictSkip,
+			stats:      Stats{SkippedPolls: 2, SkippedSchedules: 1},
+			liveTitle:  "Ужин",
+			oldTitle:   "Планирование",
+			spec:       "0 9 * * FRI",
+		},
+		{
+			name:       "overwrite",
+			onConflict: OnConflictOverwrite,
+			stats:      Stats{Polls: 1, ArchivedPolls: 1, Votes: 3, Weights: 2, Audit: 2, Schedules: 1},
+			restored:   []string{"live1", "old1"},
+			liveTitle:  "Обед",
+			oldTitle:   "Ретро",
+			spec:       "0 10 * * MON",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := existing()
+			
+			stats, err := Restore(repo, bytes.NewReader(data), tt.onConflict, false)
+			if err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+			
+			if stats != tt.stats {
+				t.Errorf("Restore() stats = %+v, want %+v", stats, tt.stats)
+			}
+			if !reflect.DeepEqual(repo.restored, tt.restored) {
+				t.Errorf("restored polls = %v, want %v", repo.restored, tt.restored)
+			}
+			if got := repo.polls["live1"].Poll.Title; got != tt.liveTitle {
+				t.Errorf("live poll title = %q, want %q", got, tt.liveTitle)
+			}
+			if got := repo.archive["old1"].Poll.Title; got != tt.oldTitle {
+				t.Errorf("archived poll title = %q, want %q", got, tt.oldTitle)
+			}
+			if got := repo.schedules["s1"].Spec; got != tt.spec {
+				t.Errorf("schedule spec = %q, want %q", got, tt.spec)
+			}
+		})
+	}
+}
+
+// Архивы версии 1 содержат только голосования, голоса и веса.
+func TestRestoreVersion1(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	
+	poll := models.Poll{ID: "v1poll", Title: "Старое", ChannelID: "c1", Options: models.NewOptions([]string{"A", "B"}), CreatedAt: testTime}
+	vote := models.Vote{PollID: "v1poll", UserID: "u1", OptionID: 1, VotedAt: testTime}
+	for _, v := range []interface{}{
+		Header{Format: Format, Version: 1, CreatedAt: testTime},
+		record{Type: recordPoll, Poll: &poll},
+		record{Type: recordVote, Vote: &vote},
+		record{Type: recordWeight, Weight: &weight{PollID: "v1poll", UserID: "u1", Weight: 2}},
+	} {
+		if err := encoder.Encode(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	
+	repo := newFakeRepository()
+	stats, err := Restore(repo, &buf, OnConflictSkip, false)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	
+	if want := (Stats{Polls: 1, Votes: 1, Weights: 1}); stats != want {
+		t.Errorf("Restore() stats = %+v, want %+v", stats, want)
+	}
+	
+	want := repository.PollSnapshot{Poll: poll, Votes: []models.Vote{vote}, Weights: map[string]int{"u1": 2}}
+	if got := repo.polls["v1poll"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("restored poll = %+v, want %+v", got, want)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/backup"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+func backupData(repo repository.PollRepository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	output := fs.String("output", "", "файл архива (по умолчанию vote-bot-<время>.jsonl.gz, - — стандартный вывод)")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("vote-bot-%s.jsonl.gz", time.Now().Format("20060102-150405"))
+	}
+	
+	if path == "-" {
+		_, err := backup.Write(repo, out)
+		return err
+	}
+	
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	
+	stats, err := backup.Write(repo, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	
+	fmt.Fprintf(out, "Резервная копия сохранена в %s: голосований %d, из архива %d, голосов %d, весов %d, записей журнала %d, расписаний %d.\n",
+		path, stats.Polls, stats.ArchivedPolls, stats.Votes, stats.Weights, stats.Audit, stats.Schedules)
+	return nil
+}
+
+func restoreData(repo repository.PollRepository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	onConflict := fs.String("on-conflict", backup.OnConflictSkip, "если голосование или расписание уже есть: skip — пропустить, overwrite — заменить вместе с голосами, весами и журналом")
+	dryRun := fs.Bool("dry-run", false, "только проверить архив и показать, что будет восстановлено")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("укажите файл архива (- — стандартный ввод)")
+	}
+	
+	var input io.Reader = os.Stdin
+	if positional[0] != "-" {
+		file, err := os.Open(positional[0])
+		if err != nil {
+			return fmt.Errorf("failed to open backup file: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+	
+	stats, err := backup.Restore(repo, input, *onConflict, *dryRun)
+	if err != nil {
+		return err
+	}
+	
+	verb := "Восстановлено"
+	if *dryRun {
+		verb = "Будет восстановлено"
+	}
+	fmt.Fprintf(out, "%s: голосований %d, из архива %d, голосов %d, весов %d, записей журнала %d, расписаний %d; пропущено существующих голосований: %d, расписаний: %d.\n",
+		verb, stats.Polls, stats.ArchivedPolls, stats.Votes, stats.Weights, stats.Audit, stats.Schedules, stats.SkippedPolls, stats.SkippedSchedules)
+	return nil
+}
\ No newline at end of file
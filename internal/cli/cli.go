@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/dew-77/mattermost-vote-system/internal/config"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+const usage = `Использование:
+  bot                                  запустить бота
+  bot polls list [--channel ID] [--status open|closed|scheduled|draft] [--json]
+  bot polls show <ID> [--json]
+  bot polls finish <ID>
+  bot polls delete <ID>
+  bot polls purge --days N [--dry-run] [--json]
+  bot votes dump <ID> [--reveal] [--json]
+  bot backup [--output FILE]
+  bot restore <FILE> [--on-conflict skip|overwrite] [--dry-run]`
+
+var errUsage = errors.New("неизвестная команда")
+
+// Run выполняет служебную команду бинарника бота. Команды работают
+// с хранилищем напрямую, без подключения к Mattermost, поэтому сообщения
+// голосований в каналах не обновляются.
+func Run(args []string, out io.Writer) error {
+	if len(args) == 1 && (args[0] == "help" || args[0] == "-h" || args[0] == "--help") {
+		fmt.Fprintln(out, usage)
+		return nil
+	}
+	command, rest, ok := lookup(args)
+	if !ok {
+		fmt.Fprintln(out, usage)
+		return errUsage
+	}
+	
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	
+	// Репозиторий подробно пишет в стандартный лог; в выводе команд это только мешает
+	log.SetOutput(io.Discard)
+	
+	repo, err := repository.NewTarantoolRepository(&cfg.Tarantool)
+	if err != nil {
+		return err
+	}
+	
+	return command(repo, rest, out)
+}
+
+type command func(repo repository.PollRepository, args []string, out io.Writer) error
+
+var commands = map[string]command{
+	"polls list":   listPolls,
+	"polls show":   showPoll,
+	"polls finish": finishPoll,
+	"polls delete": deletePoll,
+	"polls purge":  purgePolls,
+	"votes dump":   dumpVotes,
+	"backup":       backupData,
+	"restore":      restoreData,
+}
+
+// lookup находит команду из одного или двух слов и возвращает её аргументы.
+func lookup(args []string) (command, []string, bool) {
+	if len(args) >= 2 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return cmd, args[2:], true
+		}
+	}
+	cmd, ok := commands[args[0]]
+	return cmd, args[1:], ok
+}
+
+// parseFlags разбирает флаги в любом месте командной строки и возвращает
+// позиционные аргументы.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func pollIDArg(positional []string) (string, error) {
+	if len(positional) != 1 {
+		return "", errors.New("укажите ID голосования")
+	}
+	return positional[0], nil
+}
\ No newline at end of file
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+type pollRow struct {
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status"`
+	Kind       string    `json:"kind"`
+	ChannelID  string    `json:"channel_id"`
+	CreatorID  string    `json:"creator_id"`
+	Votes      int       `json:"votes"`
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+type optionRow struct {
+	Number int    `json:"number"`
+	ID     int    `json:"id"`
+	Text   string `json:"text"`
+	Votes  int    `json:"votes"`
+	Weight int    `json:"weight,omitempty"`
+}
+
+type pollDetails struct {
+	Poll    models.Poll `json:"poll"`
+	Votes   int         `json:"votes"`
+	Options []optionRow `json:"options"`
+	Outcome string      `json:"outcome,omitempty"`
+}
+
+func listPolls(repo repository.PollRepository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("polls list", flag.ContinueOnError)
+	channelID := fs.String("channel", "", "только голосования канала с этим ID")
+	status := fs.String("status", "", "только голосования с этим статусом")
+	asJSON := fs.Bool("json", false, "вывод в JSON")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	
+	polls, err := repo.ListPolls()
+	if err != nil {
+		return err
+	}
+	sort.Slice(polls, func(i, j int) bool {
+		return polls[i].CreatedAt.Before(polls[j].CreatedAt)
+	})
+	
+	rows := []pollRow{}
+	for _, poll := range polls {
+		if *channelID != "" && poll.ChannelID != *channelID {
+			continue
+		}
+		if *status != "" && poll.Status != *status {
+			continue
+		}
+		
+		votes, err := repo.GetVotes(poll.ID)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, pollRow{
+			ID:         poll.ID,
+			Title:      poll.Title,
+			Status:     poll.Status,
+			Kind:       kindName(poll.Kind),
+			ChannelID:  poll.ChannelID,
+			CreatorID:  poll.CreatorID,
+			Votes:      len(votes),
+			CreatedAt:  poll.CreatedAt,
+			FinishedAt: poll.FinishedAt,
+		})
+	}
+	
+	if *asJSON {
+		return writeJSON(out, rows)
+	}
+	
+	table := make([][]string, len(rows))
+	for i, row := range rows {
+		table[i] = []string{row.ID, truncate(row.Title, 40), row.Status, row.Kind, strconv.Itoa(row.Votes), row.ChannelID, formatTime(row.CreatedAt), formatTime(row.FinishedAt)}
+	}
+	return writeTable(out, []string{"ID", "TITLE", "STATUS", "KIND", "VOTES", "CHANNEL", "CREATED", "FINISHED"}, table)
+}
+
+func showPoll(repo repository.PollRepository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("polls show", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "вывод в JSON")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	pollID, err := pollIDArg(positional)
+	if err != nil {
+		return err
+	}
+	
+	results, err := repo.GetPollResults(pollID)
+	if err != nil {
+		return err
+	}
+	
+	details := pollDetails{
+		Poll:    results.Poll,
+		Votes:   results.TotalVotes,
+		Outcome: results.Outcome,
+	}
+	for i, option := range results.Poll.Options {
+		row := optionRow{
+			Number: i + 1,
+			ID:     option.ID,
+			Text:   option.Text,
+			Votes:  results.Results[option.ID],
+		}
+		if results.Weighted {
+			row.Weight = results.WeightedResults[option.ID]
+		}
+		details.Options = append(details.Options, row)
+	}
+	
+	if *asJSON {
+		return writeJSON(out, details)
+	}
+	
+	poll := details.Poll
+	fmt.Fprintf(out, "ID:        %s\n", poll.ID)
+	fmt.Fprintf(out, "Заголовок: %s\n", poll.Title)
+	fmt.Fprintf(out, "Статус:    %s\n", poll.Status)
+	fmt.Fprintf(out, "Тип:       %s\n", kindName(poll.Kind))
+	fmt.Fprintf(out, "Канал:     %s\n", poll.ChannelID)
+	fmt.Fprintf(out, "Создатель: %s\n", poll.CreatorID)
+	fmt.Fprintf(out, "Создано:   %s\n", formatTime(poll.CreatedAt))
+	if !poll.OpensAt.IsZero() {
+		fmt.Fprintf(out, "Открытие:  %s\n", formatTime(poll.OpensAt))
+	}
+	if !poll.ClosesAt.IsZero() {
+		fmt.Fprintf(out, "Срок:      %s\n", formatTime(poll.ClosesAt))
+	}
+	if !poll.FinishedAt.IsZero() {
+		fmt.Fprintf(out, "Завершено: %s\n", formatTime(poll.FinishedAt))
+	}
+	if poll.Anonymous {
+		fmt.Fprintln(out, "Анонимное: да")
+	}
+	fmt.Fprintf(out, "Голосов:   %d\n", details.Votes)
+	if details.Outcome != "" {
+		fmt.Fprintf(out, "Итог:      %s\n", details.Outcome)
+	}
+	fmt.Fprintln(out)
+	
+	table := make([][]string, len(details.Options))
+	for i, option := range details.Options {
+		table[i] = []string{strconv.Itoa(option.Number), truncate(option.Text, 50), strconv.Itoa(option.Votes), strconv.Itoa(option.Weight)}
+	}
+	return writeTable(out, []string{"#", "OPTION", "VOTES", "WEIGHT"}, table)
+}
+
+func finishPoll(repo repository.PollRepository, args []string, out io.Writer) error {
+	pollID, err := pollIDArg(args)
+	if err != nil {
+		return err
+	}
+	
+	poll, err := repo.GetPoll(pollID)
+	if err != nil {
+		return err
+	}
+	if poll.IsClosed() {
+		return errors.New("голосование уже завершено")
+	}
+	
+	finished, err := repo.FinishPoll(poll.ID, time.Now(), poll.Electorate)
+	if err != nil {
+		return err
+	}
+	if !finished {
+		return errors.New("голосование уже завершено")
+	}
+	recordAudit(repo, poll.ID, "finish")
+	
+	fmt.Fprintf(out, "Голосование %s завершено.\n", poll.ID)
+	return nil
+}
+
+func deletePoll(repo repository.PollRepository, args []string, out io.Writer) error {
+	pollID, err := pollIDArg(args)
+	if err != nil {
+		return err
+	}
+	
+	if _, err := repo.GetPoll(pollID); err != nil {
+		return err
+	}
+	
+	removed, err := repo.PurgePoll(pollID)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Fprintf(out, "Голосование %s удалено вместе с голосами (%d).\n", pollID, removed)
+	return nil
+}
+
+func purgePolls(repo repository.PollRepository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("polls purge", flag.ContinueOnError)
+	days := fs.Int("days", 0, "удалить голосования, завершённые раньше этого числа дней назад")
+	dryRun := fs.Bool("dry-run", false, "только показать, что будет удалено")
+	asJSON := fs.Bool("json", false, "вывод в JSON")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return errors.New("укажите --days N: сколько дней должно пройти после завершения")
+	}
+	
+	polls, err := repo.ListPolls()
+	if err != nil {
+		return err
+	}
+	
+	cutoff := time.Now().AddDate(0, 0, -*days)
+	rows := []pollRow{}
+	for _, poll := range polls {
+		if !poll.IsClosed() || poll.FinishedAt.IsZero() || !poll.FinishedAt.Before(cutoff) {
+			continue
+		}
+		
+		row := pollRow{
+			ID:         poll.ID,
+			Title:      poll.Title,
+			Status:     poll.Status,
+			Kind:       kindName(poll.Kind),
+			ChannelID:  poll.ChannelID,
+			CreatorID:  poll.CreatorID,
+			CreatedAt:  poll.CreatedAt,
+			FinishedAt: poll.FinishedAt,
+		}
+		if *dryRun {
+			votes, err := repo.GetVotes(poll.ID)
+			if err != nil {
+				return err
+			}
+			row.Votes = len(votes)
+		} else {
+			if row.Votes, err = repo.PurgePoll(poll.ID); err != nil {
+				return err
+			}
+		}
+		rows = append(rows, row)
+	}
+	
+	if *asJSON {
+		return writeJSON(out, rows)
+	}
+	
+	verb := "Удалено"
+	if *dryRun {
+		verb = "Будет удалено"
+	}
+	fmt.Fprintf(out, "%s голосований, завершённых до %s: %d\n", verb, formatTime(cutoff), len(rows))
+	for _, row := range rows {
+		fmt.Fprintf(out, "- %s «%s», голосов: %d, завершено %s\n", row.ID, row.Title, row.Votes, formatTime(row.FinishedAt))
+	}
+	return nil
+}
+
+func recordAudit(repo repository.PollRepository, pollID, action string) {
+	repo.AddAuditEntry(models.AuditEntry{
+		ID:        uuid.New().String(),
+		PollID:    pollID,
+		Action:    action,
+		Details:   "cli",
+		CreatedAt: time.Now(),
+	})
+}
+
+func kindName(kind string) string {
+	if kind == models.PollKindStandard {
+		return "standard"
+	}
+	return kind
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+	"github.com/dew-77/mattermost-vote-system/internal/repository"
+)
+
+func dumpVotes(repo repository.PollRepository, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("votes dump", flag.ContinueOnError)
+	reveal := fs.Bool("reveal", false, "показать голоса анонимного голосования")
+	asJSON := fs.Bool("json", false, "вывод в JSON")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	pollID, err := pollIDArg(positional)
+	if err != nil {
+		return err
+	}
+	
+	poll, err := repo.GetPoll(pollID)
+	if err != nil {
+		return err
+	}
+	if poll.Anonymous && !*reveal {
+		return errors.New("голосование анонимное: участникам обещано, что их выбор не раскрывается; добавьте --reveal, если это действительно нужно")
+	}
+	
+	votes, err := repo.GetVotes(pollID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].VotedAt.Before(votes[j].VotedAt)
+	})
+	
+	if *asJSON {
+		if votes == nil {
+			votes = []models.Vote{}
+		}
+		return writeJSON(out, votes)
+	}
+	
+	table := make([][]string, len(votes))
+	for i, vote := range votes {
+		table[i] = []string{vote.UserID, ballot(poll, vote), strconv.Itoa(vote.Weight), formatTime(vote.VotedAt)}
+	}
+	return writeTable(out, []string{"USER", "BALLOT", "WEIGHT", "VOTED"}, table)
+}
+
+// ballot описывает бюллетень номерами вариантов, как их видят участники.
+func ballot(poll models.Poll, vote models.Vote) string {
+	numbers := make(map[int]int, len(poll.Options))
+	for i, option := range poll.Options {
+		numbers[option.ID] = i + 1
+	}
+	number := func(optionID int) string {
+		if n, ok := numbers[optionID]; ok {
+			return strconv.Itoa(n)
+		}
+		return "?" + strconv.Itoa(optionID)
+	}
+	
+	var parts []string
+	switch {
+	case len(vote.Allocation) > 0:
+		optionIDs := make([]int, 0, len(vote.Allocation))
+		for optionID := range vote.Allocation {
+			optionIDs = append(optionIDs, optionID)
+		}
+		sort.Ints(optionIDs)
+		for _, optionID := range optionIDs {
+			parts = append(parts, number(optionID)+":"+strconv.Itoa(vote.Allocation[optionID]))
+		}
+	case len(vote.Ranking) > 0:
+		for _, optionID := range vote.Ranking {
+			parts = append(parts, number(optionID))
+		}
+		return strings.Join(parts, " > ")
+	case len(vote.Approved) > 0:
+		for _, optionID := range vote.Approved {
+			parts = append(parts, number(optionID))
+		}
+	default:
+		parts = append(parts, number(vote.OptionID))
+	}
+	return strings.Join(parts, " ")
+}
\ No newline at end of file
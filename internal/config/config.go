@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -8,6 +10,10 @@ type Config struct {
 	Mattermost MattermostConfig
 	Tarantool  TarantoolConfig
 	Bot        BotConfig
+	API        APIConfig
+	Webhooks   WebhooksConfig
+	Monitoring MonitoringConfig
+	Retention  RetentionConfig
 }
 
 type MattermostConfig struct {
@@ -26,7 +32,50 @@ type TarantoolConfig struct {
 }
 
 type BotConfig struct {
-	LogLevel string
+	LogLevel     string
+	Moderators   []string
+	GroupWeights map[string]int
+	// QuadraticCredits — бюджет кредитов по умолчанию для квадратичного голосования
+	QuadraticCredits int
+	// Reminders — за сколько до завершения голосования напоминать, например ["1h", "15m"]
+	Reminders    []string
+	ReminderMode string
+}
+
+// APIConfig — настройки HTTP API. API включается, только если задан хотя бы один токен.
+type APIConfig struct {
+	Listen string
+	Tokens []string
+}
+
+// MonitoringConfig — адрес служебных эндпоинтов (метрики Prometheus и пробы состояния).
+// Пустой адрес отключает их.
+type MonitoringConfig struct {
+	Listen string
+}
+
+// RetentionConfig — сколько хранить завершённые голосования в рабочих спейсах.
+type RetentionConfig struct {
+	// Days — через сколько дней после завершения голосование убирается; 0 отключает политику
+	Days int
+	// Action — archive переносит голосование в архив с доступом к результатам, delete удаляет
+	Action   string
+	Interval time.Duration
+}
+
+// WebhooksConfig — получатели событий голосований и правила повторной доставки.
+type WebhooksConfig struct {
+	Endpoints      []WebhookEndpoint
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Timeout        time.Duration
+}
+
+type WebhookEndpoint struct {
+	URL    string
+	Secret string
+	// Events — типы событий для отправки; пустой список означает все события
+	Events []string
 }
 
 func LoadConfig() (*Config, error) {
@@ -47,6 +96,24 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("tarantool.space", "polls")
 	
 	viper.SetDefault("bot.logLevel", "info")
+	viper.SetDefault("bot.moderators", []string{})
+	viper.SetDefault("bot.groupWeights", map[string]int{})
+	viper.SetDefault("bot.quadraticCredits", 100)
+	viper.SetDefault("bot.reminders", []string{})
+	viper.SetDefault("bot.reminderMode", "channel")
+	
+	viper.SetDefault("api.listen", ":8080")
+	viper.SetDefault("api.tokens", []string{})
+	
+	viper.SetDefault("webhooks.maxAttempts", 5)
+	viper.SetDefault("webhooks.initialBackoff", "1s")
+	viper.SetDefault("webhooks.timeout", "10s")
+	
+	viper.SetDefault("monitoring.listen", ":9090")
+	
+	viper.SetDefault("retention.days", 0)
+	viper.SetDefault("retention.action", "archive")
+	viper.SetDefault("retention.interval", "1h")
 	
 	viper.AutomaticEnv()
 	
@@ -2,9 +2,11 @@ package mattermost
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/dew-77/mattermost-vote-system/internal/config"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
 	"github.com/mattermost/mattermost-server/v6/model"
 )
 
@@ -22,6 +24,7 @@ func NewClient(cfg *config.MattermostConfig) (*Client, error) {
 
 	client := model.NewAPIv4Client(cfg.ServerURL)
 	client.SetToken(cfg.Token)
+	client.HTTPClient.Transport = monitoring.InstrumentTransport(client.HTTPClient.Transport)
 
 	_, resp, err := client.GetMe("")
 	if err != nil {
@@ -64,6 +67,40 @@ func (c *Client) CreatePost(channelID, message string) (*model.Post, error) {
 	return post, nil
 }
 
+func (c *Client) CreatePostWithFiles(channelID, message string, fileIDs []string) (*model.Post, error) {
+	post := &model.Post{
+		ChannelId: channelID,
+		Message:   message,
+		FileIds:   fileIDs,
+	}
+
+	post, resp, err := c.client.CreatePost(post)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create post: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 201 {
+		return nil, fmt.Errorf("failed to create post: status code %d", resp.StatusCode)
+	}
+
+	return post, nil
+}
+
+// UploadFile загружает файл в канал и возвращает его ID для вложения в пост.
+func (c *Client) UploadFile(channelID, filename string, data []byte) (string, error) {
+	upload, resp, err := c.client.UploadFile(data, channelID, filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload file: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 201 {
+		return "", fmt.Errorf("failed to upload file: status code %d", resp.StatusCode)
+	}
+	if len(upload.FileInfos) == 0 {
+		return "", fmt.Errorf("failed to upload file: empty response")
+	}
+
+	return upload.FileInfos[0].Id, nil
+}
+
 func (c *Client) UpdatePost(post *model.Post) (*model.Post, error) {
 	post, resp, err := c.client.UpdatePost(post.Id, post)
 	if err != nil {
@@ -88,6 +125,18 @@ func (c *Client) DeletePost(postID string) error {
 	return nil
 }
 
+func (c *Client) SendDirectMessage(userID, message string) (*model.Post, error) {
+	channel, resp, err := c.client.CreateDirectChannel(c.botUserID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create direct channel: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return nil, fmt.Errorf("failed to create direct channel: status code %d", resp.StatusCode)
+	}
+
+	return c.CreatePost(channel.Id, message)
+}
+
 func (c *Client) GetUser(userID string) (*model.User, error) {
 	user, resp, err := c.client.GetUser(userID, "")
 	if err != nil {
@@ -100,6 +149,144 @@ func (c *Client) GetUser(userID string) (*model.User, error) {
 	return user, nil
 }
 
+func (c *Client) GetChannel(channelID string) (*model.Channel, error) {
+	channel, resp, err := c.client.GetChannel(channelID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get channel: status code %d", resp.StatusCode)
+	}
+
+	return channel, nil
+}
+
+func (c *Client) GetChannelMember(channelID, userID string) (*model.ChannelMember, error) {
+	member, resp, err := c.client.GetChannelMember(channelID, userID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get channel member: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get channel member: status code %d", resp.StatusCode)
+	}
+
+	return member, nil
+}
+
+func (c *Client) IsChannelMember(channelID, userID string) (bool, error) {
+	_, resp, err := c.client.GetChannelMember(channelID, userID, "")
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get channel member: %v", err)
+	}
+
+	return true, nil
+}
+
+func (c *Client) GetChannelMemberIDs(channelID string) ([]string, error) {
+	const perPage = 200
+
+	var userIDs []string
+	for page := 0; ; page++ {
+		members, resp, err := c.client.GetChannelMembers(channelID, page, perPage, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get channel members: %v", err)
+		}
+		if resp != nil && resp.StatusCode != 200 {
+			return nil, fmt.Errorf("failed to get channel members: status code %d", resp.StatusCode)
+		}
+
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserId)
+		}
+		if len(members) < perPage {
+			return userIDs, nil
+		}
+	}
+}
+
+func (c *Client) GetTeamMember(userID string) (*model.TeamMember, error) {
+	member, resp, err := c.client.GetTeamMember(c.teamID, userID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team member: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get team member: status code %d", resp.StatusCode)
+	}
+
+	return member, nil
+}
+
+func (c *Client) GetUsersByUsernames(usernames []string) ([]*model.User, error) {
+	users, resp, err := c.client.GetUsersByUsernames(usernames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get users: status code %d", resp.StatusCode)
+	}
+
+	return users, nil
+}
+
+func (c *Client) GetGroupByName(name string) (*model.Group, error) {
+	groups, resp, err := c.client.GetGroups(model.GroupSearchOpts{Q: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get groups: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to get groups: status code %d", resp.StatusCode)
+	}
+
+	for _, group := range groups {
+		if group.Name != nil && strings.EqualFold(*group.Name, name) {
+			return group, nil
+		}
+	}
+
+	return nil, fmt.Errorf("group %q not found", name)
+}
+
+func (c *Client) GetGroupMemberIDs(groupID string) ([]string, error) {
+	const perPage = 200
+
+	var userIDs []string
+	for page := 0; ; page++ {
+		users, resp, err := c.client.GetUsersInGroup(groupID, page, perPage, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group members: %v", err)
+		}
+		if resp != nil && resp.StatusCode != 200 {
+			return nil, fmt.Errorf("failed to get group members: status code %d", resp.StatusCode)
+		}
+
+		for _, user := range users {
+			userIDs = append(userIDs, user.Id)
+		}
+		if len(users) < perPage {
+			return userIDs, nil
+		}
+	}
+}
+
+// Ping проверяет, что сервер Mattermost доступен и отвечает.
+func (c *Client) Ping() error {
+	status, resp, err := c.client.GetPing()
+	if err != nil {
+		return fmt.Errorf("failed to ping Mattermost: %v", err)
+	}
+	if resp != nil && resp.StatusCode != 200 {
+		return fmt.Errorf("failed to ping Mattermost: status code %d", resp.StatusCode)
+	}
+	if status != model.StatusOk {
+		return fmt.Errorf("failed to ping Mattermost: status %q", status)
+	}
+
+	return nil
+}
+
 func (c *Client) GetBotUserID() string {
 	return c.botUserID
 }
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+type AuditEntry struct {
+	ID        string    `json:"id"`
+	PollID    string    `json:"poll_id"`
+	UserID    string    `json:"user_id"`
+	Action    string    `json:"action"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
\ No newline at end of file
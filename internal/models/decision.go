@@ -0,0 +1,169 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	ThresholdMajority  = "majority"
+	ThresholdTwoThirds = "two_thirds"
+	ThresholdUnanimity = "unanimity"
+)
+
+const (
+	PollKindStandard  = ""
+	PollKindProposal  = "proposal"
+	PollKindQuadratic = "quadratic"
+	PollKindSTV       = "stv"
+	PollKindApproval  = "approval"
+)
+
+// Состояния голосования: черновик до публикации, объявленное с отложенным
+// открытием, открытое для голосования и завершённое.
+const (
+	PollStatusDraft     = "draft"
+	PollStatusScheduled = "scheduled"
+	PollStatusOpen      = "open"
+	PollStatusClosed    = "closed"
+)
+
+// Способы напоминания: сообщение в канал или личные сообщения
+// тем, кто ещё не проголосовал.
+const (
+	ReminderModeChannel = "channel"
+	ReminderModeDM      = "dm"
+)
+
+// Фиксированные варианты предложения «да / нет / воздержаться».
+const (
+	ProposalYes = iota
+	ProposalNo
+	ProposalAbstain
+)
+
+const (
+	TieBreakReport   = "report"
+	TieBreakCreator  = "creator"
+	TieBreakEarliest = "earliest"
+	TieBreakRandom   = "random"
+)
+
+const (
+	OutcomePassed  = "passed"
+	OutcomeFailed  = "failed"
+	OutcomeInvalid = "invalid"
+)
+
+// Winner описывает победителя голосования и то, как была разрешена ничья.
+type Winner struct {
+	OptionID      int    `json:"option_id"`
+	Votes         int    `json:"votes"`
+	Tie           bool   `json:"tie"`
+	TiedOptionIDs []int  `json:"tied_option_ids,omitempty"`
+	TieBreak      string `json:"tie_break,omitempty"`
+	Resolved      bool   `json:"resolved"`
+	Seed          int64  `json:"seed,omitempty"`
+}
+
+// Quorum задаёт минимальную явку: либо абсолютным числом голосов,
+// либо процентом от числа имеющих право голоса.
+type Quorum struct {
+	Count   int     `json:"count,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+}
+
+func (q Quorum) IsSet() bool {
+	return q.Count > 0 || q.Percent > 0
+}
+
+// Required возвращает число голосов, необходимое для кворума.
+func (q Quorum) Required(electorate int) int {
+	if q.Count > 0 {
+		return q.Count
+	}
+	return int(math.Ceil(q.Percent * float64(electorate) / 100))
+}
+
+func (q Quorum) String() string {
+	if q.Count > 0 {
+		return strconv.Itoa(q.Count)
+	}
+	return strconv.FormatFloat(q.Percent, 'f', -1, 64) + "%"
+}
+
+func ParseQuorum(s string) (Quorum, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return Quorum{}, fmt.Errorf("invalid quorum percentage %q", s)
+		}
+		return Quorum{Percent: percent}, nil
+	}
+
+	count, err := strconv.Atoi(s)
+	if err != nil || count <= 0 {
+		return Quorum{}, fmt.Errorf("invalid quorum %q", s)
+	}
+	return Quorum{Count: count}, nil
+}
+
+func ParseThreshold(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "majority", "simple", "50%":
+		return ThresholdMajority, nil
+	case "2/3", "two_thirds", "two-thirds":
+		return ThresholdTwoThirds, nil
+	case "unanimity", "unanimous", "100%":
+		return ThresholdUnanimity, nil
+	}
+
+	if strings.HasSuffix(s, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err == nil && percent > 0 && percent < 100 {
+			return strconv.FormatFloat(percent, 'f', -1, 64) + "%", nil
+		}
+	}
+	return "", fmt.Errorf("unknown threshold %q", s)
+}
+
+// ThresholdMet проверяет, набрал ли вариант с support голосами нужную долю от total.
+// Помимо именованных порогов поддерживается процент вида "60%" (не меньше указанного).
+func ThresholdMet(threshold string, support, total int) bool {
+	if total == 0 {
+		return false
+	}
+
+	switch threshold {
+	case ThresholdTwoThirds:
+		return support*3 >= total*2
+	case ThresholdUnanimity:
+		return support == total
+	}
+
+	if strings.HasSuffix(threshold, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		if err == nil {
+			return float64(support)*100 >= percent*float64(total)
+		}
+	}
+	return support*2 > total
+}
+
+func ParseTieBreak(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "report", "tie":
+		return TieBreakReport, nil
+	case "creator", "casting":
+		return TieBreakCreator, nil
+	case "earliest", "earliest-vote", "first":
+		return TieBreakEarliest, nil
+	case "random", "draw", "lot":
+		return TieBreakRandom, nil
+	}
+	return "", fmt.Errorf("unknown tie-break policy %q", s)
+}
\ No newline at end of file
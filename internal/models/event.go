@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Типы событий жизненного цикла голосования.
+const (
+	EventPollCreated  = "poll.created"
+	EventVoteCast     = "vote.cast"
+	EventPollFinished = "poll.finished"
+	EventPollDeleted  = "poll.deleted"
+)
+
+type Event struct {
+	ID         string       `json:"id"`
+	Type       string       `json:"type"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	UserID     string       `json:"user_id,omitempty"`
+	Poll       Poll         `json:"poll"`
+	Vote       *Vote        `json:"vote,omitempty"`
+	Results    *PollResults `json:"results,omitempty"`
+}
+
+// DeadLetter — вебхук, который не удалось доставить после всех попыток.
+type DeadLetter struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	EventType string    `json:"event_type"`
+	Payload   string    `json:"payload"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
\ No newline at end of file
@@ -4,20 +4,176 @@ import (
 	"time"
 )
 
+type Option struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
 type Poll struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Options     []string  `json:"options"`
-	CreatorID   string    `json:"creator_id"`
-	ChannelID   string    `json:"channel_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	FinishedAt  time.Time `json:"finished_at,omitempty"`
-	IsFinished  bool      `json:"is_finished"`
-	PostID      string    `json:"post_id"`
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Options        []Option  `json:"options"`
+	CreatorID      string    `json:"creator_id"`
+	ChannelID      string    `json:"channel_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	FinishedAt     time.Time `json:"finished_at,omitempty"`
+	Status         string    `json:"status"`
+	PostID         string    `json:"post_id"`
+	ClosesAt       time.Time `json:"closes_at,omitempty"`
+	EligibleVoters []string  `json:"eligible_voters,omitempty"`
+	EligibleGroups []string  `json:"eligible_groups,omitempty"`
+	Quorum         Quorum    `json:"quorum,omitempty"`
+	Threshold      string    `json:"threshold,omitempty"`
+	Electorate     int       `json:"electorate,omitempty"`
+	TieBreak       string    `json:"tie_break,omitempty"`
+	TieSeed        int64     `json:"tie_seed,omitempty"`
+	CastingVote    *int      `json:"casting_vote,omitempty"`
+	Kind           string    `json:"kind,omitempty"`
+	Credits        int       `json:"credits,omitempty"`
+	Seats          int       `json:"seats,omitempty"`
+	OpensAt        time.Time `json:"opens_at,omitempty"`
+	// Reminders — за сколько до завершения напомнить о голосовании;
+	// если не заданы, используются значения из конфигурации
+	Reminders     []time.Duration `json:"reminders,omitempty"`
+	ReminderMode  string          `json:"reminder_mode,omitempty"`
+	RemindersSent []time.Duration `json:"reminders_sent,omitempty"`
+	// Anonymous скрывает, кто как проголосовал: наружу отдаются только итоги
+	Anonymous bool `json:"anonymous,omitempty"`
+}
+
+// IsOpen сообщает, принимает ли голосование голоса.
+func (p Poll) IsOpen() bool {
+	return p.Status == PollStatusOpen
+}
+
+// IsScheduled сообщает, что голосование объявлено, но ещё не открыто.
+func (p Poll) IsScheduled() bool {
+	return p.Status == PollStatusScheduled
+}
+
+// IsClosed сообщает, что голосование завершено.
+func (p Poll) IsClosed() bool {
+	return p.Status == PollStatusClosed
+}
+
+// HasVoterList сообщает, ограничен ли круг голосующих списком участников или групп.
+func (p Poll) HasVoterList() bool {
+	return len(p.EligibleVoters) > 0 || len(p.EligibleGroups) > 0
+}
+
+// IsBinding сообщает, что для голосования заданы кворум или порог принятия решения.
+func (p Poll) IsBinding() bool {
+	return p.Quorum.IsSet() || p.Threshold != "" || p.IsProposal()
+}
+
+func (p Poll) IsProposal() bool {
+	return p.Kind == PollKindProposal
+}
+
+func (p Poll) IsQuadratic() bool {
+	return p.Kind == PollKindQuadratic
+}
+
+func (p Poll) IsSTV() bool {
+	return p.Kind == PollKindSTV
+}
+
+func (p Poll) IsApproval() bool {
+	return p.Kind == PollKindApproval
+}
+
+// HasEditableOptions сообщает, можно ли добавлять и удалять варианты:
+// в предложениях они фиксированы, а в бюллетенях с несколькими вариантами
+// удаление исказило бы уже отданные голоса.
+func (p Poll) HasEditableOptions() bool {
+	return p.Kind == PollKindStandard
+}
+
+// OptionByID ищет вариант по его постоянному идентификатору.
+func (p Poll) OptionByID(id int) (Option, bool) {
+	for _, option := range p.Options {
+		if option.ID == id {
+			return option, true
+		}
+	}
+	return Option{}, false
+}
+
+// NextOptionID возвращает идентификатор для нового варианта.
+func (p Poll) NextOptionID() int {
+	next := 0
+	for _, option := range p.Options {
+		if option.ID >= next {
+			next = option.ID + 1
+		}
+	}
+	return next
+}
+
+func NewProposalOptions() []Option {
+	return []Option{
+		{ID: ProposalYes, Text: "За"},
+		{ID: ProposalNo, Text: "Против"},
+		{ID: ProposalAbstain, Text: "Воздержаться"},
+	}
+}
+
+func NewOptions(texts []string) []Option {
+	options := make([]Option, len(texts))
+	for i, text := range texts {
+		options[i] = Option{ID: i, Text: text}
+	}
+	return options
 }
 
 type PollResults struct {
-	Poll    Poll             `json:"poll"`
-	Results map[string]int   `json:"results"`
-	Voters  map[string]string `json:"voters"`
+	Poll            Poll           `json:"poll"`
+	Results         map[int]int    `json:"results"`
+	Voters          map[string]int `json:"voters"`
+	TotalVotes      int            `json:"total_votes"`
+	Electorate      int            `json:"electorate,omitempty"`
+	Turnout         float64        `json:"turnout,omitempty"`
+	QuorumRequired  int            `json:"quorum_required,omitempty"`
+	QuorumMet       bool           `json:"quorum_met"`
+	Outcome         string         `json:"outcome,omitempty"`
+	Winner          *Winner        `json:"winner,omitempty"`
+	Approval        float64        `json:"approval,omitempty"`
+	Weighted        bool           `json:"weighted"`
+	WeightedResults map[int]int    `json:"weighted_results,omitempty"`
+	TotalWeight     int            `json:"total_weight,omitempty"`
+	CreditsSpent    map[int]int    `json:"credits_spent,omitempty"`
+	TotalCredits    int            `json:"total_credits,omitempty"`
+	STV             *STVResult     `json:"stv,omitempty"`
+	// Ranking — варианты по убыванию числа одобрений
+	Ranking []int `json:"ranking,omitempty"`
+	// ApprovalRates — доля проголосовавших, одобривших вариант, в процентах
+	ApprovalRates map[int]float64 `json:"approval_rates,omitempty"`
+	// Archived — голосование перенесено в архив и доступно только для чтения
+	Archived bool `json:"archived,omitempty"`
+}
+
+// Tally возвращает итоги, по которым определяется победитель:
+// взвешенные, если в голосовании есть веса, иначе обычные.
+func (r PollResults) Tally() map[int]int {
+	if r.Weighted {
+		return r.WeightedResults
+	}
+	return r.Results
+}
+
+// DecisiveTotal возвращает сумму голосов, от которой считается порог.
+// В квадратичном голосовании у каждого участника может быть несколько
+// голосов, поэтому считаются голоса по всем вариантам, а не участники.
+func (r PollResults) DecisiveTotal() int {
+	if r.Weighted {
+		return r.TotalWeight
+	}
+	if r.Poll.IsQuadratic() {
+		total := 0
+		for _, votes := range r.Results {
+			total += votes
+		}
+		return total
+	}
+	return r.TotalVotes
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// Schedule — повторяющееся голосование: по расписанию Spec (формат cron)
+// бот создаёт голосование командой create с аргументами Args.
+type Schedule struct {
+	ID        string    `json:"id"`
+	CreatorID string    `json:"creator_id"`
+	ChannelID string    `json:"channel_id"`
+	Spec      string    `json:"spec"`
+	Args      []string  `json:"args"`
+	CreatedAt time.Time `json:"created_at"`
+	NextRunAt time.Time `json:"next_run_at"`
+	LastRunAt time.Time `json:"last_run_at,omitempty"`
+	LastPoll  string    `json:"last_poll,omitempty"`
+}
\ No newline at end of file
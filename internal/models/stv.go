@@ -0,0 +1,27 @@
+package models
+
+// STVResult — итоги выборов единым передаваемым голосом с журналом раундов.
+type STVResult struct {
+	Seats   int        `json:"seats"`
+	Ballots float64    `json:"ballots"`
+	Quota   float64    `json:"quota"`
+	Elected []int      `json:"elected"`
+	Rounds  []STVRound `json:"rounds"`
+}
+
+type STVRound struct {
+	Number     int             `json:"round"`
+	Tallies    map[int]float64 `json:"tallies"`
+	Exhausted  float64         `json:"exhausted"`
+	Elected    []int           `json:"elected,omitempty"`
+	Eliminated []int           `json:"eliminated,omitempty"`
+	Transfers  []STVTransfer   `json:"transfers,omitempty"`
+}
+
+// STVTransfer описывает передачу голосов избранного (излишек) или
+// выбывшего (все голоса) кандидата следующим предпочтениям.
+type STVTransfer struct {
+	From   int     `json:"from"`
+	Votes  float64 `json:"votes"`
+	Factor float64 `json:"factor"`
+}
\ No newline at end of file
@@ -1,12 +1,38 @@
 package models
 
 import (
+	"errors"
 	"time"
 )
 
+// Ошибки проверки права голоса, общие для команд чата и HTTP API.
+var (
+	ErrNotChannelMember = errors.New("user is not a member of the poll channel")
+	ErrNotEligible      = errors.New("user is not on the poll voter list")
+)
+
 type Vote struct {
-	PollID    string    `json:"poll_id"`
-	UserID    string    `json:"user_id"`
-	OptionIdx int       `json:"option_idx"`
-	VotedAt   time.Time `json:"voted_at"`
+	PollID   string    `json:"poll_id"`
+	UserID   string    `json:"user_id"`
+	OptionID int       `json:"option_id"`
+	VotedAt  time.Time `json:"voted_at"`
+	Weight   int       `json:"weight,omitempty"`
+	// Allocation — распределение голосов по вариантам в квадратичном голосовании
+	Allocation map[int]int `json:"allocation,omitempty"`
+	// Ranking — варианты в порядке предпочтения для ранжированных бюллетеней
+	Ranking []int `json:"ranking,omitempty"`
+	// Approved — множество одобренных вариантов в голосовании одобрением
+	Approved []int `json:"approved,omitempty"`
+}
+
+// NoOption ставится в OptionID бюллетеней, где выбрано несколько вариантов.
+const NoOption = -1
+
+// Cost возвращает стоимость бюллетеня в кредитах: сумму квадратов голосов.
+func (v Vote) Cost() int {
+	cost := 0
+	for _, votes := range v.Allocation {
+		cost += votes * votes
+	}
+	return cost
 }
\ No newline at end of file
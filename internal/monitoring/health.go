@@ -0,0 +1,93 @@
+package monitoring
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout ограничивает время одной проверки зависимости.
+const healthCheckTimeout = 3 * time.Second
+
+// Check — проверка одной зависимости бота для /readyz.
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+type checkResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks,omitempty"`
+}
+
+// handleHealthz отвечает, пока процесс жив; зависимости не проверяются,
+// чтобы недоступность Tarantool или Mattermost не приводила к перезапуску бота.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// handleReadyz проверяет все зависимости параллельно и отвечает 503,
+// если хотя бы одна из них недоступна.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	results := make(map[string]checkResult, len(s.checks))
+	
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := runCheck(check)
+			
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+	
+	response := healthResponse{Status: "ok", Checks: results}
+	status := http.StatusOK
+	for name, result := range results {
+		if result.Status != "ok" {
+			response.Status = "fail"
+			status = http.StatusServiceUnavailable
+			s.logger.WithField("check", name).WithField("error", result.Error).Warn("Readiness check failed")
+		}
+	}
+	
+	writeJSON(w, status, response)
+}
+
+func runCheck(check Check) checkResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Run()
+	}()
+	
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(healthCheckTimeout):
+		err = errCheckTimeout
+	}
+	
+	result := checkResult{
+		Status:    "ok",
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = "fail"
+		result.Error = err.Error()
+	}
+	return result
+}
\ No newline at end of file
@@ -0,0 +1,151 @@
+package monitoring
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "vote_bot"
+
+// Исходы команд бота.
+const (
+	OutcomeOK      = "ok"
+	OutcomeError   = "error"
+	OutcomeUnknown = "unknown"
+)
+
+var (
+	CommandsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_total",
+		Help:      "Commands received by the bot, by command and outcome.",
+	}, []string{"command", "outcome"})
+
+	VotesCast = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "votes_cast_total",
+		Help:      "Ballots accepted, by poll kind and source.",
+	}, []string{"kind", "source"})
+
+	PollsCreated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "polls_created_total",
+		Help:      "Polls created, by poll kind.",
+	}, []string{"kind"})
+
+	PollsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "polls_finished_total",
+		Help:      "Polls finished, by trigger (manual or deadline).",
+	}, []string{"trigger"})
+
+	TarantoolRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "tarantool_request_duration_seconds",
+		Help:      "Latency of Tarantool requests, by operation and space.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"operation", "space"})
+
+	TarantoolErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tarantool_errors_total",
+		Help:      "Failed Tarantool requests, by operation and space.",
+	}, []string{"operation", "space"})
+
+	MattermostRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mattermost_request_duration_seconds",
+		Help:      "Latency of Mattermost API requests, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	MattermostErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "mattermost_errors_total",
+		Help:      "Failed Mattermost API requests (transport errors and 4xx/5xx), by method and route.",
+	}, []string{"method", "route"})
+
+	WebSocketReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "websocket_reconnects_total",
+		Help:      "Reconnections of the Mattermost WebSocket.",
+	})
+
+	EventQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "event_queue_depth",
+		Help:      "Events waiting for webhook delivery.",
+	})
+)
+
+// PollKind возвращает значение метки для типа голосования.
+func PollKind(kind string) string {
+	if kind == "" {
+		return "standard"
+	}
+	return kind
+}
+
+// ObserveTarantool учитывает длительность и ошибку запроса к Tarantool.
+func ObserveTarantool(operation, space string, start time.Time, err error) {
+	TarantoolRequestDuration.WithLabelValues(operation, space).Observe(time.Since(start).Seconds())
+	if err != nil {
+		TarantoolErrors.WithLabelValues(operation, space).Inc()
+	}
+}
+
+// InstrumentTransport оборачивает HTTP-транспорт клиента Mattermost,
+// чтобы учитывать длительность и ошибки всех запросов к API.
+func InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		route := Route(req.URL.Path)
+		start := time.Now()
+		
+		resp, err := next.RoundTrip(req)
+		
+		MattermostRequestDuration.WithLabelValues(req.Method, route).Observe(time.Since(start).Seconds())
+		if err != nil || resp.StatusCode >= http.StatusBadRequest {
+			MattermostErrors.WithLabelValues(req.Method, route).Inc()
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+var mattermostID = regexp.MustCompile(`^[a-z0-9]{26}$`)
+
+// Route заменяет в пути запроса идентификаторы и имена на заполнители,
+// чтобы число значений метки оставалось ограниченным.
+func Route(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case mattermostID.MatchString(segment):
+			segments[i] = "{id}"
+		case i > 0 && (segments[i-1] == "name" || segments[i-1] == "username"):
+			segments[i] = "{name}"
+		case isNumber(segment):
+			segments[i] = "{n}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"github.com/dew-77/mattermost-vote-system/internal/config"
+)
+
+var errCheckTimeout = errors.New("check timed out")
+
+// Server отдаёт служебные эндпоинты бота: метрики Prometheus
+// и пробы живости и готовности.
+type Server struct {
+	logger *logrus.Logger
+	checks []Check
+	server *http.Server
+}
+
+func NewServer(cfg *config.MonitoringConfig, logger *logrus.Logger, checks ...Check) *Server {
+	s := &Server{
+		logger: logger,
+		checks: checks,
+	}
+	
+	s.server = &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	
+	return s
+}
+
+// Enabled сообщает, задан ли адрес для служебных эндпоинтов.
+func (s *Server) Enabled() bool {
+	return s.server.Addr != ""
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+	mux.HandleFunc("GET /readyz", s.handleReadyz)
+	
+	return mux
+}
+
+func (s *Server) Start() error {
+	s.logger.WithField("listen", s.server.Addr).Info("Starting monitoring server")
+	
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
\ No newline at end of file
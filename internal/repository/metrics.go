@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tarantool/go-tarantool"
+	"github.com/dew-77/mattermost-vote-system/internal/monitoring"
+)
+
+// instrumentedConn учитывает длительность и ошибки запросов к Tarantool
+// в метриках Prometheus.
+type instrumentedConn struct {
+	*tarantool.Connection
+}
+
+func (c *instrumentedConn) Ping() (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Ping()
+	monitoring.ObserveTarantool("ping", "", start, err)
+	return resp, err
+}
+
+func (c *instrumentedConn) Select(space, index interface{}, offset, limit, iterator uint32, key interface{}) (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Select(space, index, offset, limit, iterator, key)
+	monitoring.ObserveTarantool("select", fmt.Sprint(space), start, err)
+	return resp, err
+}
+
+func (c *instrumentedConn) Insert(space interface{}, tuple interface{}) (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Insert(space, tuple)
+	monitoring.ObserveTarantool("insert", fmt.Sprint(space), start, err)
+	return resp, err
+}
+
+func (c *instrumentedConn) Replace(space interface{}, tuple interface{}) (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Replace(space, tuple)
+	monitoring.ObserveTarantool("replace", fmt.Sprint(space), start, err)
+	return resp, err
+}
+
+func (c *instrumentedConn) Update(space, index interface{}, key, ops interface{}) (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Update(space, index, key, ops)
+	monitoring.ObserveTarantool("update", fmt.Sprint(space), start, err)
+	return resp, err
+}
+
+func (c *instrumentedConn) Delete(space, index interface{}, key interface{}) (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Delete(space, index, key)
+	monitoring.ObserveTarantool("delete", fmt.Sprint(space), start, err)
+	return resp, err
+}
+
+func (c *instrumentedConn) Eval(expr string, args interface{}) (*tarantool.Response, error) {
+	start := time.Now()
+	resp, err := c.Connection.Eval(expr, args)
+	monitoring.ObserveTarantool("eval", "", start, err)
+	return resp, err
+}
\ No newline at end of file
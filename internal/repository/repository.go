@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/dew-77/mattermost-vote-system/internal/models"
 )
 
@@ -8,10 +11,69 @@ type PollRepository interface {
 	CreatePoll(poll models.Poll) error
 	GetPoll(pollID string) (models.Poll, error)
 	UpdatePoll(poll models.Poll) error
+	// SetRemindersSent меняет только список отправленных напоминаний,
+	// не перезаписывая остальные поля голосования.
+	SetRemindersSent(pollID string, sent []time.Duration) error
+	// SetPollStatus меняет статус голосования, только если текущий статус
+	// равен from, и сообщает, была ли выполнена замена.
+	SetPollStatus(pollID, from, to string) (bool, error)
+	// FinishPoll закрывает открытое голосование, записывая время завершения
+	// и число участников, и сообщает, было ли оно ещё открыто.
+	FinishPoll(pollID string, finishedAt time.Time, electorate int) (bool, error)
 	DeletePoll(pollID string) error
+	ListPolls() ([]models.Poll, error)
 
 	AddVote(vote models.Vote) error
+	AddQuadraticVote(vote models.Vote) error
+	RemoveVote(pollID, userID string) error
+	RemoveOptionVotes(pollID string, optionID int) ([]string, error)
 	GetVotes(pollID string) ([]models.Vote, error)
 
+	SetWeight(pollID, userID string, weight int) error
+	GetWeights(pollID string) (map[string]int, error)
+
 	GetPollResults(pollID string) (models.PollResults, error)
+	
+	// ArchivePoll переносит голосование с голосами, весами и журналом действий
+	// в архив, где его результаты остаются доступны только для чтения.
+	// PurgePoll удаляет всё это безвозвратно. Оба возвращают число перенесённых или удалённых голосов.
+	ArchivePoll(pollID string) (int, error)
+	PurgePoll(pollID string) (int, error)
+	ListArchivedPolls() ([]PollSnapshot, error)
+	
+	// RestorePoll записывает снимок голосования одной транзакцией: голосование
+	// с тем же ID заменяется, а его голоса, веса и журнал, которых нет в снимке,
+	// удаляются. Снимок с ArchivedAt записывается в архив.
+	RestorePoll(snapshot PollSnapshot) error
+
+	AddAuditEntry(entry models.AuditEntry) error
+	GetAuditEntries(pollID string) ([]models.AuditEntry, error)
+
+	CreateSchedule(schedule models.Schedule) error
+	GetSchedule(scheduleID string) (models.Schedule, error)
+	UpdateSchedule(schedule models.Schedule) error
+	DeleteSchedule(scheduleID string) error
+	ListSchedules() ([]models.Schedule, error)
+
+	AddDeadLetter(letter models.DeadLetter) error
+	ListDeadLetters() ([]models.DeadLetter, error)
+	DeleteDeadLetter(letterID string) error
 }
+
+// PollSnapshot — голосование вместе с голосами, весами и журналом действий.
+// ArchivedAt задан, если голосование хранится в архиве.
+type PollSnapshot struct {
+	Poll       models.Poll
+	Votes      []models.Vote
+	Weights    map[string]int
+	Audit      []models.AuditEntry
+	ArchivedAt time.Time
+}
+
+var (
+	ErrPollNotFound   = errors.New("poll not found")
+	ErrVoteNotFound   = errors.New("vote not found")
+	ErrBudgetExceeded = errors.New("credit budget exceeded")
+	ErrPollFinished   = errors.New("poll is finished")
+	ErrPollNotOpen    = errors.New("poll is not open yet")
+)
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// computeResults подсчитывает голоса и, для голосований с кворумом или порогом,
+// явку и итог. Не зависит от хранилища, чтобы другие реализации
+// PollRepository считали результаты одинаково. weights — таблица весов
+// голосования; она переопределяет вес, сохранённый в самом голосе.
+func computeResults(poll models.Poll, votes []models.Vote, weights map[string]int) models.PollResults {
+	results := make(map[int]int)
+	weighted := make(map[int]int)
+	for _, option := range poll.Options {
+		results[option.ID] = 0
+		weighted[option.ID] = 0
+	}
+	
+	voters := make(map[string]int)
+	credits := make(map[int]int)
+	totalWeight := 0
+	totalCredits := 0
+	isWeighted := false
+	
+	for _, vote := range votes {
+		allocation := ballotAllocation(poll, vote)
+		if len(allocation) == 0 {
+			continue
+		}
+		
+		weight := effectiveWeight(vote, weights)
+		if weight != 1 {
+			isWeighted = true
+		}
+		
+		if poll.IsApproval() {
+			// Вес голосующего учитывается один раз, сколько бы вариантов он ни одобрил
+			totalWeight += weight
+		}
+		
+		for optionID, count := range allocation {
+			results[optionID] += count
+			weighted[optionID] += count * weight
+			if !poll.IsApproval() {
+				totalWeight += count * weight
+			}
+			credits[optionID] += count * count
+			totalCredits += count * count
+		}
+		
+		voters[vote.UserID] = vote.OptionID
+	}
+	
+	pollResults := models.PollResults{
+		Poll:       poll,
+		Results:    results,
+		Voters:     voters,
+		TotalVotes: len(voters),
+		Electorate: poll.Electorate,
+	}
+	
+	if poll.IsQuadratic() {
+		pollResults.CreditsSpent = credits
+		pollResults.TotalCredits = totalCredits
+	}
+	
+	if isWeighted {
+		pollResults.Weighted = true
+		pollResults.WeightedResults = weighted
+		pollResults.TotalWeight = totalWeight
+	}
+	
+	if poll.IsApproval() {
+		rankApprovals(&pollResults)
+	}
+	
+	if poll.IsSTV() {
+		pollResults.STV = computeSTV(poll, votes, weights)
+	} else if !poll.IsProposal() {
+		pollResults.Winner = computeWinner(poll, pollResults.Tally(), votes)
+	}
+	
+	if poll.Electorate > 0 {
+		pollResults.Turnout = float64(pollResults.TotalVotes) / float64(poll.Electorate) * 100
+	}
+	
+	if poll.IsBinding() {
+		evaluateOutcome(&pollResults)
+	}
+	
+	return pollResults
+}
+
+// ballotAllocation приводит бюллетень к числу голосов по вариантам,
+// отбрасывая варианты, которых больше нет в голосовании.
+func ballotAllocation(poll models.Poll, vote models.Vote) map[int]int {
+	allocation := make(map[int]int)
+	
+	if poll.IsQuadratic() {
+		for optionID, count := range vote.Allocation {
+			if _, ok := poll.OptionByID(optionID); ok && count > 0 {
+				allocation[optionID] = count
+			}
+		}
+		return allocation
+	}
+	
+	if poll.IsApproval() {
+		for _, optionID := range vote.Approved {
+			if _, ok := poll.OptionByID(optionID); ok {
+				allocation[optionID] = 1
+			}
+		}
+		return allocation
+	}
+	
+	if poll.IsSTV() {
+		// В первичных итогах учитывается только первое действительное предпочтение
+		if ranking := validRanking(poll, vote.Ranking); len(ranking) > 0 {
+			allocation[ranking[0]] = 1
+		}
+		return allocation
+	}
+	
+	if _, ok := poll.OptionByID(vote.OptionID); ok {
+		allocation[vote.OptionID] = 1
+	}
+	return allocation
+}
+
+// rankApprovals упорядочивает варианты по числу одобрений и считает долю
+// одобривших от числа проголосовавших (или их суммарного веса), а не от
+// общего числа отметок.
+func rankApprovals(results *models.PollResults) {
+	tally := results.Tally()
+	total := results.DecisiveTotal()
+	
+	order := make(map[int]int, len(results.Poll.Options))
+	results.Ranking = make([]int, 0, len(results.Poll.Options))
+	results.ApprovalRates = make(map[int]float64, len(results.Poll.Options))
+	for i, option := range results.Poll.Options {
+		order[option.ID] = i
+		results.Ranking = append(results.Ranking, option.ID)
+		if total > 0 {
+			results.ApprovalRates[option.ID] = float64(tally[option.ID]) / float64(total) * 100
+		} else {
+			results.ApprovalRates[option.ID] = 0
+		}
+	}
+	
+	sort.SliceStable(results.Ranking, func(i, j int) bool {
+		a, b := results.Ranking[i], results.Ranking[j]
+		if tally[a] != tally[b] {
+			return tally[a] > tally[b]
+		}
+		return order[a] < order[b]
+	})
+}
+
+func effectiveWeight(vote models.Vote, weights map[string]int) int {
+	if weight, ok := weights[vote.UserID]; ok {
+		return weight
+	}
+	if vote.Weight > 0 {
+		return vote.Weight
+	}
+	return 1
+}
+
+// computeWinner находит вариант с наибольшим числом голосов. Если первое место
+// делят несколько вариантов, ничья разрешается по правилу голосования:
+// решающий голос создателя, кто раньше набрал итоговое число голосов
+// или жребий с опубликованным seed.
+func computeWinner(poll models.Poll, results map[int]int, votes []models.Vote) *models.Winner {
+	best := 0
+	var tied []int
+	for _, option := range poll.Options {
+		count := results[option.ID]
+		switch {
+		case count > best:
+			best, tied = count, []int{option.ID}
+		case count == best && count > 0:
+			tied = append(tied, option.ID)
+		}
+	}
+	
+	if best == 0 {
+		return nil
+	}
+	
+	if len(tied) == 1 {
+		return &models.Winner{OptionID: tied[0], Votes: best, Resolved: true}
+	}
+	
+	winner := &models.Winner{
+		OptionID:      -1,
+		Votes:         best,
+		Tie:           true,
+		TiedOptionIDs: tied,
+		TieBreak:      poll.TieBreak,
+	}
+	
+	switch poll.TieBreak {
+	case models.TieBreakCreator:
+		if poll.CastingVote != nil && slices.Contains(tied, *poll.CastingVote) {
+			winner.OptionID = *poll.CastingVote
+		}
+	case models.TieBreakEarliest:
+		winner.OptionID = earliestToReach(poll, tied, votes)
+	case models.TieBreakRandom:
+		sorted := append([]int(nil), tied...)
+		sort.Ints(sorted)
+		rng := rand.New(rand.NewSource(poll.TieSeed))
+		winner.OptionID = sorted[rng.Intn(len(sorted))]
+		winner.Seed = poll.TieSeed
+	}
+	
+	winner.Resolved = winner.OptionID >= 0
+	return winner
+}
+
+// earliestToReach выбирает вариант, последний голос за который был отдан раньше
+// остальных, то есть тот, что первым набрал итоговое число голосов.
+func earliestToReach(poll models.Poll, tied []int, votes []models.Vote) int {
+	lastVote := make(map[int]time.Time, len(tied))
+	for _, vote := range votes {
+		for optionID := range ballotAllocation(poll, vote) {
+			if vote.VotedAt.After(lastVote[optionID]) {
+				lastVote[optionID] = vote.VotedAt
+			}
+		}
+	}
+	
+	winnerID := -1
+	for _, optionID := range tied {
+		if winnerID < 0 || lastVote[optionID].Before(lastVote[winnerID]) {
+			winnerID = optionID
+		}
+	}
+	return winnerID
+}
+
+func evaluateOutcome(results *models.PollResults) {
+	poll := results.Poll
+	
+	results.QuorumMet = true
+	if poll.Quorum.IsSet() {
+		results.QuorumRequired = poll.Quorum.Required(results.Electorate)
+		results.QuorumMet = results.TotalVotes >= results.QuorumRequired
+		// Процентный кворум без числа участников проверить нельзя: иначе
+		// для него хватило бы и нуля голосов
+		if poll.Quorum.Count == 0 && results.Electorate == 0 {
+			results.QuorumMet = false
+		}
+	}
+	
+	if poll.IsProposal() {
+		evaluateProposal(results)
+		return
+	}
+	
+	if poll.IsSTV() {
+		// Выборы состоялись, если при соблюдении кворума заняты все места
+		switch {
+		case !results.QuorumMet:
+			results.Outcome = models.OutcomeInvalid
+		case results.STV != nil && len(results.STV.Elected) == results.STV.Seats:
+			results.Outcome = models.OutcomePassed
+		default:
+			results.Outcome = models.OutcomeFailed
+		}
+		return
+	}
+	
+	switch {
+	case !results.QuorumMet:
+		results.Outcome = models.OutcomeInvalid
+	case results.Winner != nil && results.Winner.Resolved && models.ThresholdMet(poll.Threshold, results.Winner.Votes, results.DecisiveTotal()):
+		results.Outcome = models.OutcomePassed
+	default:
+		results.Outcome = models.OutcomeFailed
+	}
+}
+
+// evaluateProposal считает одобрение только по голосам «за» и «против»:
+// воздержавшиеся учитываются в явке и кворуме, но не в доле одобрения.
+func evaluateProposal(results *models.PollResults) {
+	tally := results.Tally()
+	yes := tally[models.ProposalYes]
+	decisive := yes + tally[models.ProposalNo]
+	
+	if decisive > 0 {
+		results.Approval = float64(yes) / float64(decisive) * 100
+	}
+	
+	switch {
+	case !results.QuorumMet:
+		results.Outcome = models.OutcomeInvalid
+	case models.ThresholdMet(results.Poll.Threshold, yes, decisive):
+		results.Outcome = models.OutcomePassed
+	default:
+		results.Outcome = models.OutcomeFailed
+	}
+}
\ No newline at end of file
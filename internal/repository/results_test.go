@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+func quadraticVote(userID string, allocation map[int]int) models.Vote {
+	return models.Vote{PollID: "q1", UserID: userID, OptionID: models.NoOption, Allocation: allocation}
+}
+
+// Порог квадратичного голосования считается от суммы голосов по всем
+// вариантам, а не от числа проголосовавших.
+func TestQuadraticThresholdUsesEffectiveVotes(t *testing.T) {
+	poll := models.Poll{
+		ID:        "q1",
+		Kind:      models.PollKindQuadratic,
+		Options:   models.NewOptions([]string{"A", "B", "C"}),
+		Credits:   9,
+		Threshold: models.ThresholdMajority,
+	}
+	
+	tests := []struct {
+		name     string
+		votes    []models.Vote
+		winner   int
+		winVotes int
+		total    int
+		outcome  string
+	}{
+		{
+			// A: 3 из 6 голосов — ровно половина, но больше половины из 3 участников
+			name: "plurality below majority",
+			votes: []models.Vote{
+				quadraticVote("u1", map[int]int{0: 2}),
+				quadraticVote("u2", map[int]int{1: 2}),
+				quadraticVote("u3", map[int]int{0: 1, 2: 1}),
+			},
+			winner:   0,
+			winVotes: 3,
+			total:    6,
+			outcome:  models.OutcomeFailed,
+		},
+		{
+			name: "majority of effective votes",
+			votes: []models.Vote{
+				quadraticVote("u1", map[int]int{0: 3}),
+				quadraticVote("u2", map[int]int{0: 1, 1: 1}),
+				quadraticVote("u3", map[int]int{1: 1}),
+			},
+			winner:   0,
+			winVotes: 4,
+			total:    6,
+			outcome:  models.OutcomePassed,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := computeResults(poll, tt.votes, nil)
+			
+			if results.TotalVotes != 3 {
+				t.Errorf("TotalVotes = %d, want 3 voters", results.TotalVotes)
+			}
+			if got := results.DecisiveTotal(); got != tt.total {
+				t.Errorf("DecisiveTotal() = %d, want %d", got, tt.total)
+			}
+			if results.Winner == nil || results.Winner.OptionID != tt.winner || results.Winner.Votes != tt.winVotes {
+				t.Fatalf("winner = %+v, want option %d with %d votes", results.Winner, tt.winner, tt.winVotes)
+			}
+			if results.Outcome != tt.outcome {
+				t.Errorf("Outcome = %q, want %q", results.Outcome, tt.outcome)
+			}
+		})
+	}
+}
+
+// Процентный кворум при неизвестном числе участников не считается
+// достигнутым, даже если голоса есть.
+func TestPercentQuorumWithUnknownElectorate(t *testing.T) {
+	votes := []models.Vote{
+		{PollID: "p1", UserID: "u1", OptionID: 0},
+		{PollID: "p1", UserID: "u2", OptionID: 0},
+	}
+	
+	tests := []struct {
+		name       string
+		quorum     models.Quorum
+		electorate int
+		met        bool
+		outcome    string
+	}{
+		{name: "percent without electorate", quorum: models.Quorum{Percent: 50}, electorate: 0, met: false, outcome: models.OutcomeInvalid},
+		{name: "percent with electorate", quorum: models.Quorum{Percent: 50}, electorate: 4, met: true, outcome: models.OutcomePassed},
+		{name: "count without electorate", quorum: models.Quorum{Count: 2}, electorate: 0, met: true, outcome: models.OutcomePassed},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := models.Poll{
+				ID:         "p1",
+				Kind:       models.PollKindStandard,
+				Options:    models.NewOptions([]string{"A", "B"}),
+				Quorum:     tt.quorum,
+				Electorate: tt.electorate,
+			}
+			
+			results := computeResults(poll, votes, nil)
+			
+			if results.QuorumMet != tt.met {
+				t.Errorf("QuorumMet = %v, want %v", results.QuorumMet, tt.met)
+			}
+			if results.Outcome != tt.outcome {
+				t.Errorf("Outcome = %q, want %q", results.Outcome, tt.outcome)
+			}
+		})
+	}
+}
+
+// Доля одобрения считается от числа проголосовавших (или их веса),
+// а не от общего числа отметок.
+func TestRankApprovals(t *testing.T) {
+	votes := []models.Vote{
+		{PollID: "a1", UserID: "u1", OptionID: models.NoOption, Approved: []int{0, 1}},
+		{PollID: "a1", UserID: "u2", OptionID: models.NoOption, Approved: []int{1}},
+		{PollID: "a1", UserID: "u3", OptionID: models.NoOption, Approved: []int{1, 2}},
+		{PollID: "a1", UserID: "u4", OptionID: models.NoOption, Approved: []int{3}},
+	}
+	
+	tests := []struct {
+		name    string
+		weights map[string]int
+		ranking []int
+		rates   map[int]float64
+	}{
+		{
+			// A и C одобрены по разу: порядок между ними — как в голосовании
+			name:    "unweighted",
+			ranking: []int{1, 0, 2, 3},
+			rates:   map[int]float64{0: 25, 1: 75, 2: 25, 3: 25},
+		},
+		{
+			name:    "weighted",
+			weights: map[string]int{"u3": 3, "u4": 5},
+			ranking: []int{1, 3, 2, 0},
+			rates:   map[int]float64{0: 10, 1: 50, 2: 30, 3: 50},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := models.Poll{
+				ID:      "a1",
+				Kind:    models.PollKindApproval,
+				Options: models.NewOptions([]string{"A", "B", "C", "D"}),
+			}
+			
+			results := computeResults(poll, votes, tt.weights)
+			
+			if !slices.Equal(results.Ranking, tt.ranking) {
+				t.Errorf("Ranking = %v, want %v", results.Ranking, tt.ranking)
+			}
+			for optionID, want := range tt.rates {
+				if got := results.ApprovalRates[optionID]; got != want {
+					t.Errorf("ApprovalRates[%d] = %v, want %v", optionID, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeWinnerTieBreak(t *testing.T) {
+	const a, b, c = 0, 1, 2
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	vote := func(userID string, optionID int, minutes int) models.Vote {
+		return models.Vote{PollID: "t1", UserID: userID, OptionID: optionID, VotedAt: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+	
+	// A и B набрали по два голоса; B — на минуту раньше
+	votes := []models.Vote{
+		vote("u1", a, 1),
+		vote("u2", b, 2),
+		vote("u3", b, 3),
+		vote("u4", a, 4),
+		vote("u5", c, 5),
+	}
+	castingVote := func(optionID int) *int { return &optionID }
+	
+	tests := []struct {
+		name        string
+		tieBreak    string
+		castingVote *int
+		seed        int64
+		winner      int
+		resolved    bool
+	}{
+		{name: "report", tieBreak: models.TieBreakReport, winner: -1},
+		{name: "creator", tieBreak: models.TieBreakCreator, castingVote: castingVote(a), winner: a, resolved: true},
+		{name: "creator without casting vote", tieBreak: models.TieBreakCreator, winner: -1},
+		{name: "creator casting vote outside tie", tieBreak: models.TieBreakCreator, castingVote: castingVote(c), winner: -1},
+		{name: "earliest", tieBreak: models.TieBreakEarliest, winner: b, resolved: true},
+		// Исход жребия зафиксирован для seed: при смене генератора он изменится
+		{name: "random seed 1", tieBreak: models.TieBreakRandom, seed: 1, winner: b, resolved: true},
+		{name: "random seed 2", tieBreak: models.TieBreakRandom, seed: 2, winner: a, resolved: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := models.Poll{
+				ID:          "t1",
+				Kind:        models.PollKindStandard,
+				Options:     models.NewOptions([]string{"A", "B", "C"}),
+				TieBreak:    tt.tieBreak,
+				CastingVote: tt.castingVote,
+				TieSeed:     tt.seed,
+			}
+			
+			winner := computeWinner(poll, map[int]int{a: 2, b: 2, c: 1}, votes)
+			
+			if winner == nil {
+				t.Fatal("winner = nil")
+			}
+			if !winner.Tie || !slices.Equal(winner.TiedOptionIDs, []int{a, b}) {
+				t.Errorf("tie = %v %v, want true [%d %d]", winner.Tie, winner.TiedOptionIDs, a, b)
+			}
+			if winner.OptionID != tt.winner || winner.Resolved != tt.resolved {
+				t.Errorf("winner = %d (resolved %v), want %d (resolved %v)", winner.OptionID, winner.Resolved, tt.winner, tt.resolved)
+			}
+			if winner.Votes != 2 {
+				t.Errorf("Votes = %d, want 2", winner.Votes)
+			}
+			if tt.tieBreak == models.TieBreakRandom {
+				if winner.Seed != tt.seed {
+					t.Errorf("Seed = %d, want %d", winner.Seed, tt.seed)
+				}
+				// Порядок голосов не влияет на исход жребия
+				reversed := slices.Clone(votes)
+				slices.Reverse(reversed)
+				if again := computeWinner(poll, map[int]int{a: 2, b: 2, c: 1}, reversed); again.OptionID != winner.OptionID {
+					t.Errorf("repeated draw = %d, want %d", again.OptionID, winner.OptionID)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeWinnerWithoutTie(t *testing.T) {
+	poll := models.Poll{ID: "t1", Options: models.NewOptions([]string{"A", "B"}), TieBreak: models.TieBreakReport}
+	
+	if winner := computeWinner(poll, map[int]int{0: 0, 1: 0}, nil); winner != nil {
+		t.Errorf("winner without votes = %+v, want nil", winner)
+	}
+	
+	winner := computeWinner(poll, map[int]int{0: 1, 1: 3}, nil)
+	if winner == nil || winner.OptionID != 1 || !winner.Resolved || winner.Tie {
+		t.Errorf("winner = %+v, want resolved option 1 without tie", winner)
+	}
+}
+
+// Из вариантов с равным числом голосов выигрывает тот, чей последний голос
+// отдан раньше; варианты вне ничьей не учитываются.
+func TestEarliestToReach(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	poll := models.Poll{
+		ID:      "e1",
+		Kind:    models.PollKindApproval,
+		Options: models.NewOptions([]string{"A", "B", "C"}),
+	}
+	votes := []models.Vote{
+		{UserID: "u1", OptionID: models.NoOption, Approved: []int{0, 1}, VotedAt: start},
+		{UserID: "u2", OptionID: models.NoOption, Approved: []int{2}, VotedAt: start.Add(time.Minute)},
+		{UserID: "u3", OptionID: models.NoOption, Approved: []int{0}, VotedAt: start.Add(2 * time.Minute)},
+		{UserID: "u4", OptionID: models.NoOption, Approved: []int{1, 2}, VotedAt: start.Add(3 * time.Minute)},
+	}
+	
+	tests := []struct {
+		name   string
+		tied   []int
+		winner int
+	}{
+		{name: "earlier last vote wins", tied: []int{0, 1}, winner: 0},
+		{name: "order of tied options does not matter", tied: []int{1, 0}, winner: 0},
+		{name: "equal last votes keep first tied option", tied: []int{2, 1}, winner: 2},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := earliestToReach(poll, tt.tied, votes); got != tt.winner {
+				t.Errorf("earliestToReach(%v) = %d, want %d", tt.tied, got, tt.winner)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+const stvEpsilon = 1e-9
+
+type stvBallot struct {
+	ranking []int
+	weight  float64
+	// position — индекс текущего предпочтения в ranking
+	position int
+}
+
+// computeSTV проводит выборы единым передаваемым голосом: квота Друпа,
+// передача излишков методом взвешенного включающего Грегори (все бюллетени
+// избранного кандидата передаются с понижающим коэффициентом) и исключение
+// кандидата с наименьшим числом голосов, если никто не набрал квоту.
+func computeSTV(poll models.Poll, votes []models.Vote, weights map[string]int) *models.STVResult {
+	seats := poll.Seats
+	if seats < 1 {
+		seats = 1
+	}
+	
+	var ballots []*stvBallot
+	total := 0.0
+	for _, vote := range votes {
+		ranking := validRanking(poll, vote.Ranking)
+		weight := float64(effectiveWeight(vote, weights))
+		if len(ranking) == 0 || weight <= 0 {
+			continue
+		}
+		ballots = append(ballots, &stvBallot{ranking: ranking, weight: weight})
+		total += weight
+	}
+	
+	result := &models.STVResult{
+		Seats:   seats,
+		Ballots: total,
+		Quota:   math.Floor(total/float64(seats+1)) + 1,
+	}
+	
+	// Без бюллетеней никто не избран: иначе все кандидаты прошли бы
+	// с нулём голосов, когда их не больше, чем мест
+	if len(ballots) == 0 {
+		return result
+	}
+	
+	continuing := make(map[int]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		continuing[option.ID] = true
+	}
+	
+	var history []map[int]float64
+	
+	for len(result.Elected) < seats && len(continuing) > 0 {
+		tallies, exhausted := stvTally(ballots, continuing)
+		history = append(history, tallies)
+		
+		round := models.STVRound{
+			Number:    len(result.Rounds) + 1,
+			Tallies:   roundTallies(tallies),
+			Exhausted: roundVotes(exhausted),
+		}
+		
+		if len(result.Elected)+len(continuing) <= seats {
+			// Оставшихся кандидатов не больше, чем свободных мест
+			for _, optionID := range rankByTally(poll, tallies, continuing) {
+				round.Elected = append(round.Elected, optionID)
+				result.Elected = append(result.Elected, optionID)
+				delete(continuing, optionID)
+			}
+			result.Rounds = append(result.Rounds, round)
+			break
+		}
+		
+		var reached []int
+		for _, optionID := range rankByTally(poll, tallies, continuing) {
+			if tallies[optionID]+stvEpsilon >= result.Quota {
+				reached = append(reached, optionID)
+			}
+		}
+		
+		if len(reached) > 0 {
+			for _, optionID := range reached {
+				if len(result.Elected) >= seats {
+					break
+				}
+				
+				round.Elected = append(round.Elected, optionID)
+				result.Elected = append(result.Elected, optionID)
+				delete(continuing, optionID)
+				
+				surplus := tallies[optionID] - result.Quota
+				factor := 0.0
+				if tallies[optionID] > 0 && surplus > 0 {
+					factor = surplus / tallies[optionID]
+				}
+				
+				if surplus > stvEpsilon {
+					round.Transfers = append(round.Transfers, models.STVTransfer{
+						From:   optionID,
+						Votes:  roundVotes(surplus),
+						Factor: math.Round(factor*10000) / 10000,
+					})
+				}
+				
+				for _, ballot := range ballots {
+					if ballot.position < len(ballot.ranking) && ballot.ranking[ballot.position] == optionID {
+						ballot.weight *= factor
+					}
+				}
+			}
+		} else {
+			eliminated := lowestCandidate(poll, history, continuing)
+			round.Eliminated = append(round.Eliminated, eliminated)
+			round.Transfers = append(round.Transfers, models.STVTransfer{
+				From:   eliminated,
+				Votes:  roundVotes(tallies[eliminated]),
+				Factor: 1,
+			})
+			delete(continuing, eliminated)
+		}
+		
+		result.Rounds = append(result.Rounds, round)
+	}
+	
+	return result
+}
+
+// stvTally переносит каждый бюллетень к первому ещё участвующему кандидату
+// и возвращает голоса кандидатов и вес исчерпанных бюллетеней.
+func stvTally(ballots []*stvBallot, continuing map[int]bool) (map[int]float64, float64) {
+	tallies := make(map[int]float64, len(continuing))
+	for optionID := range continuing {
+		tallies[optionID] = 0
+	}
+	
+	exhausted := 0.0
+	for _, ballot := range ballots {
+		for ballot.position < len(ballot.ranking) && !continuing[ballot.ranking[ballot.position]] {
+			ballot.position++
+		}
+		
+		if ballot.position >= len(ballot.ranking) {
+			exhausted += ballot.weight
+			continue
+		}
+		tallies[ballot.ranking[ballot.position]] += ballot.weight
+	}
+	
+	return tallies, exhausted
+}
+
+// rankByTally упорядочивает участвующих кандидатов по убыванию голосов,
+// при равенстве — по порядку вариантов в голосовании.
+func rankByTally(poll models.Poll, tallies map[int]float64, continuing map[int]bool) []int {
+	var ranked []int
+	order := make(map[int]int, len(poll.Options))
+	for i, option := range poll.Options {
+		order[option.ID] = i
+		if continuing[option.ID] {
+			ranked = append(ranked, option.ID)
+		}
+	}
+	
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := tallies[ranked[i]], tallies[ranked[j]]
+		if math.Abs(a-b) > stvEpsilon {
+			return a > b
+		}
+		return order[ranked[i]] < order[ranked[j]]
+	})
+	return ranked
+}
+
+// lowestCandidate выбирает кандидата на исключение. Равенство разрешается
+// по предыдущим раундам (исключается тот, у кого было меньше голосов раньше),
+// а если оно сохраняется во всех раундах — исключается последний по порядку вариант.
+func lowestCandidate(poll models.Poll, history []map[int]float64, continuing map[int]bool) int {
+	current := history[len(history)-1]
+	ranked := rankByTally(poll, current, continuing)
+	lowest := current[ranked[len(ranked)-1]]
+	
+	var tied []int
+	for _, optionID := range ranked {
+		if math.Abs(current[optionID]-lowest) <= stvEpsilon {
+			tied = append(tied, optionID)
+		}
+	}
+	
+	for i := len(history) - 2; i >= 0 && len(tied) > 1; i-- {
+		min := math.Inf(1)
+		for _, optionID := range tied {
+			min = math.Min(min, history[i][optionID])
+		}
+		
+		var next []int
+		for _, optionID := range tied {
+			if math.Abs(history[i][optionID]-min) <= stvEpsilon {
+				next = append(next, optionID)
+			}
+		}
+		tied = next
+	}
+	
+	return tied[len(tied)-1]
+}
+
+func validRanking(poll models.Poll, ranking []int) []int {
+	seen := make(map[int]bool, len(ranking))
+	var valid []int
+	for _, optionID := range ranking {
+		if _, ok := poll.OptionByID(optionID); ok && !seen[optionID] {
+			seen[optionID] = true
+			valid = append(valid, optionID)
+		}
+	}
+	return valid
+}
+
+func roundTallies(tallies map[int]float64) map[int]float64 {
+	rounded := make(map[int]float64, len(tallies))
+	for optionID, votes := range tallies {
+		rounded[optionID] = roundVotes(votes)
+	}
+	return rounded
+}
+
+func roundVotes(votes float64) float64 {
+	return math.Round(votes*10000) / 10000
+}
\ No newline at end of file
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/dew-77/mattermost-vote-system/internal/models"
+)
+
+// rankedVotes повторяет бюллетень с ранжированием count раз.
+func rankedVotes(votes []models.Vote, count int, ranking ...int) []models.Vote {
+	for i := 0; i < count; i++ {
+		userID := fmt.Sprintf("u%d", len(votes)+1)
+		votes = append(votes, models.Vote{PollID: "s1", UserID: userID, OptionID: models.NoOption, Ranking: ranking})
+	}
+	return votes
+}
+
+func TestComputeSTV(t *testing.T) {
+	const a, b, c = 0, 1, 2
+	
+	tests := []struct {
+		name    string
+		seats   int
+		votes   []models.Vote
+		quota   float64
+		elected []int
+		rounds  int
+		check   func(t *testing.T, result *models.STVResult)
+	}{
+		{
+			// 12 бюллетеней на 2 места: квота ⌊12/3⌋+1 = 5, излишек A (3 из 8)
+			// переходит к B с коэффициентом 3/8
+			name:    "surplus transfer",
+			seats:   2,
+			votes:   rankedVotes(rankedVotes(rankedVotes(nil, 8, a, b), 2, c), 2, b),
+			quota:   5,
+			elected: []int{a, b},
+			rounds:  2,
+			check: func(t *testing.T, result *models.STVResult) {
+				want := models.STVTransfer{From: a, Votes: 3, Factor: 0.375}
+				if got := result.Rounds[0].Transfers; len(got) != 1 || got[0] != want {
+					t.Errorf("round 1 transfers = %+v, want [%+v]", got, want)
+				}
+				if got := result.Rounds[1].Tallies[b]; got != 5 {
+					t.Errorf("round 2 tally of B = %v, want 5", got)
+				}
+			},
+		},
+		{
+			// Никто не набрал квоту 5 из 9: выбывает C, его голоса уходят к B
+			name:    "elimination",
+			seats:   1,
+			votes:   rankedVotes(rankedVotes(rankedVotes(nil, 4, a), 3, b), 2, c, b),
+			quota:   5,
+			elected: []int{b},
+			rounds:  2,
+			check: func(t *testing.T, result *models.STVResult) {
+				if got := result.Rounds[0].Eliminated; !slices.Equal(got, []int{c}) {
+					t.Errorf("round 1 eliminated = %v, want [%d]", got, c)
+				}
+				if got := result.Rounds[1].Tallies[b]; got != 5 {
+					t.Errorf("round 2 tally of B = %v, want 5", got)
+				}
+			},
+		},
+		{
+			// Бюллетени без следующих предпочтений выбывших кандидатов исчерпываются;
+			// при равенстве B и C исключается последний по порядку вариант
+			name:    "exhausted ballots",
+			seats:   1,
+			votes:   rankedVotes(rankedVotes(rankedVotes(nil, 3, a), 2, b), 2, c),
+			quota:   4,
+			elected: []int{a},
+			rounds:  3,
+			check: func(t *testing.T, result *models.STVResult) {
+				if got := result.Rounds[0].Eliminated; !slices.Equal(got, []int{c}) {
+					t.Errorf("round 1 eliminated = %v, want [%d]", got, c)
+				}
+				for i, want := range []float64{0, 2, 4} {
+					if got := result.Rounds[i].Exhausted; got != want {
+						t.Errorf("round %d exhausted = %v, want %v", i+1, got, want)
+					}
+				}
+			},
+		},
+		{
+			name:    "no ballots",
+			seats:   2,
+			votes:   nil,
+			quota:   1,
+			elected: nil,
+			rounds:  0,
+		},
+		{
+			name:    "only invalid ballots",
+			seats:   1,
+			votes:   rankedVotes(nil, 3, 7, 8),
+			quota:   1,
+			elected: nil,
+			rounds:  0,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := models.Poll{
+				ID:      "s1",
+				Kind:    models.PollKindSTV,
+				Options: models.NewOptions([]string{"A", "B", "C"}),
+				Seats:   tt.seats,
+			}
+			
+			result := computeSTV(poll, tt.votes, nil)
+			
+			if result.Quota != tt.quota {
+				t.Errorf("Quota = %v, want %v", result.Quota, tt.quota)
+			}
+			if !slices.Equal(result.Elected, tt.elected) {
+				t.Errorf("Elected = %v, want %v", result.Elected, tt.elected)
+			}
+			if len(result.Rounds) != tt.rounds {
+				t.Fatalf("rounds = %d, want %d: %+v", len(result.Rounds), tt.rounds, result.Rounds)
+			}
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+		})
+	}
+}
+
+// Выборы без бюллетеней не состоялись: места остались незанятыми.
+func TestSTVWithoutBallotsFails(t *testing.T) {
+	poll := models.Poll{
+		ID:        "s1",
+		Kind:      models.PollKindSTV,
+		Options:   models.NewOptions([]string{"A", "B"}),
+		Seats:     2,
+		Threshold: models.ThresholdMajority,
+	}
+	
+	results := computeResults(poll, nil, nil)
+	
+	if results.Outcome != models.OutcomeFailed {
+		t.Errorf("Outcome = %q, want %q", results.Outcome, models.OutcomeFailed)
+	}
+}
\ No newline at end of file
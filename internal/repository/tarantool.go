@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/tarantool/go-tarantool"
@@ -11,7 +14,7 @@ import (
 )
 
 type TarantoolRepository struct {
-	conn   *tarantool.Connection
+	conn   *instrumentedConn
 	config *config.TarantoolConfig
 }
 
@@ -69,24 +72,14 @@ func NewTarantoolRepository(cfg *config.TarantoolConfig) (*TarantoolRepository,
 	}
 	
 	return &TarantoolRepository{
-		conn:   conn,
+		conn:   &instrumentedConn{conn},
 		config: cfg,
 	}, nil
 }
 
 func (r *TarantoolRepository) CreatePoll(poll models.Poll) error {
 	log.Printf("Creating poll with ID: %s", poll.ID)
-	resp, err := r.conn.Insert("polls", []interface{}{
-		poll.ID,
-		poll.Title,
-		poll.Options,
-		poll.CreatorID,
-		poll.ChannelID,
-		poll.CreatedAt,
-		poll.FinishedAt,
-		poll.IsFinished,
-		poll.PostID,
-	})
+	resp, err := r.conn.Insert("polls", pollToTuple(poll))
 	
 	if err != nil {
 		log.Printf("ERROR: Failed to create poll: %v", err)
@@ -108,36 +101,19 @@ func (r *TarantoolRepository) GetPoll(pollID string) (models.Poll, error) {
 	
 	if len(resp.Data) == 0 {
 		log.Printf("Poll not found with ID: %s", pollID)
-		return models.Poll{}, fmt.Errorf("poll not found")
+		return models.Poll{}, ErrPollNotFound
 	}
 	
 	tuples := resp.Tuples()
 	if len(tuples) == 0 {
 		log.Printf("Poll tuples empty for ID: %s", pollID)
-		return models.Poll{}, fmt.Errorf("poll not found")
+		return models.Poll{}, ErrPollNotFound
 	}
 	
 	tuple := tuples[0]
 	log.Printf("Retrieved poll tuple: %v", tuple)
 	
-	var poll models.Poll
-	poll.ID = tuple[0].(string)
-	poll.Title = tuple[1].(string)
-	
-	optionsInterface := tuple[2].([]interface{})
-	poll.Options = make([]string, len(optionsInterface))
-	for i, opt := range optionsInterface {
-		poll.Options[i] = opt.(string)
-	}
-	
-	poll.CreatorID = tuple[3].(string)
-	poll.ChannelID = tuple[4].(string)
-	poll.CreatedAt = tuple[5].(time.Time)
-	if tuple[6] != nil {
-		poll.FinishedAt = tuple[6].(time.Time)
-	}
-	poll.IsFinished = tuple[7].(bool)
-	poll.PostID = tuple[8].(string)
+	poll := tupleToPoll(tuple)
 	
 	log.Printf("Successfully retrieved poll: %s - %s", poll.ID, poll.Title)
 	return poll, nil
@@ -146,17 +122,7 @@ func (r *TarantoolRepository) GetPoll(pollID string) (models.Poll, error) {
 func (r *TarantoolRepository) UpdatePoll(poll models.Poll) error {
 	log.Printf("Updating poll with ID: %s", poll.ID)
 	
-	resp, err := r.conn.Replace("polls", []interface{}{
-		poll.ID,
-		poll.Title,
-		poll.Options,
-		poll.CreatorID,
-		poll.ChannelID,
-		poll.CreatedAt,
-		poll.FinishedAt,
-		poll.IsFinished,
-		poll.PostID,
-	})
+	resp, err := r.conn.Replace("polls", pollToTuple(poll))
 	
 	if err != nil {
 		log.Printf("ERROR: Failed to update poll: %v", err)
@@ -167,6 +133,85 @@ func (r *TarantoolRepository) UpdatePoll(poll models.Poll) error {
 	return nil
 }
 
+func (r *TarantoolRepository) SetRemindersSent(pollID string, sent []time.Duration) error {
+	log.Printf("Updating sent reminders of poll %s", pollID)
+	
+	ops := []interface{}{[]interface{}{"=", 24, encodeDurations(sent)}}
+	_, err := r.conn.Update("polls", "primary", []interface{}{pollID}, ops)
+	if err != nil {
+		log.Printf("ERROR: Failed to update sent reminders: %v", err)
+		return fmt.Errorf("failed to update sent reminders: %w", err)
+	}
+	
+	return nil
+}
+
+// transitionPoll сравнивает и заменяет статус в одной транзакции, чтобы не
+// затереть изменения, сделанные после чтения голосования. ops — остальные
+// поля, которые меняются вместе со статусом. Булев статус — из старых
+// версий схемы, как в decodeStatus.
+const transitionPoll = `
+local poll_id, from, ops = ...
+return box.atomic(function()
+    local poll = box.space.polls:get(poll_id)
+    if poll == nil then
+        return false
+    end
+    local status = poll[8]
+    if status == true then
+        status = 'closed'
+    elseif type(status) ~= 'string' then
+        status = 'open'
+    end
+    if status ~= from then
+        return false
+    end
+    box.space.polls:update(poll_id, ops)
+    return true
+end)
+`
+
+func (r *TarantoolRepository) SetPollStatus(pollID, from, to string) (bool, error) {
+	log.Printf("Changing status of poll %s from %s to %s", pollID, from, to)
+	
+	changed, err := r.transitionPoll(pollID, from, []interface{}{
+		[]interface{}{"=", 8, to},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to change poll status: %w", err)
+	}
+	return changed, nil
+}
+
+func (r *TarantoolRepository) FinishPoll(pollID string, finishedAt time.Time, electorate int) (bool, error) {
+	log.Printf("Finishing poll %s", pollID)
+	
+	changed, err := r.transitionPoll(pollID, models.PollStatusOpen, []interface{}{
+		[]interface{}{"=", 8, models.PollStatusClosed},
+		[]interface{}{"=", 7, finishedAt},
+		[]interface{}{"=", 15, electorate},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to finish poll: %w", err)
+	}
+	return changed, nil
+}
+
+func (r *TarantoolRepository) transitionPoll(pollID, from string, ops []interface{}) (bool, error) {
+	resp, err := r.conn.Eval(transitionPoll, []interface{}{pollID, from, ops})
+	if err != nil {
+		log.Printf("ERROR: Failed to change poll status: %v", err)
+		return false, err
+	}
+	
+	if len(resp.Data) < 1 {
+		return false, fmt.Errorf("unexpected response %v", resp.Data)
+	}
+	
+	changed, _ := resp.Data[0].(bool)
+	return changed, nil
+}
+
 func (r *TarantoolRepository) DeletePoll(pollID string) error {
 	log.Printf("Deleting poll with ID: %s", pollID)
 	
@@ -180,8 +225,27 @@ func (r *TarantoolRepository) DeletePoll(pollID string) error {
 	return nil
 }
 
+func (r *TarantoolRepository) ListPolls() ([]models.Poll, error) {
+	log.Printf("Listing polls")
+	
+	resp, err := r.conn.Select("polls", "primary", 0, math.MaxUint32, tarantool.IterAll, []interface{}{})
+	if err != nil {
+		log.Printf("ERROR: Failed to list polls: %v", err)
+		return nil, fmt.Errorf("failed to list polls: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	polls := make([]models.Poll, len(tuples))
+	for i, tuple := range tuples {
+		polls[i] = tupleToPoll(tuple)
+	}
+	
+	log.Printf("Retrieved %d polls", len(polls))
+	return polls, nil
+}
+
 func (r *TarantoolRepository) AddVote(vote models.Vote) error {
-	log.Printf("Adding vote for poll %s by user %s for option %d", vote.PollID, vote.UserID, vote.OptionIdx)
+	log.Printf("Adding vote for poll %s by user %s for option %d", vote.PollID, vote.UserID, vote.OptionID)
 	
 	resp, err := r.conn.Delete("votes", "user_poll", []interface{}{vote.UserID, vote.PollID})
 	if err != nil {
@@ -190,12 +254,7 @@ func (r *TarantoolRepository) AddVote(vote models.Vote) error {
 	}
 	log.Printf("Previous vote deleted (if any): %v", resp)
 	
-	resp, err = r.conn.Insert("votes", []interface{}{
-		vote.PollID,
-		vote.UserID,
-		vote.OptionIdx,
-		vote.VotedAt,
-	})
+	resp, err = r.conn.Insert("votes", voteToTuple(vote))
 	
 	if err != nil {
 		log.Printf("ERROR: Failed to add vote: %v", err)
@@ -206,10 +265,86 @@ func (r *TarantoolRepository) AddVote(vote models.Vote) error {
 	return nil
 }
 
+// castQuadraticVote проверяет бюджет по числу кредитов, сохранённому в самом
+// голосовании, и заменяет бюллетень в одной транзакции.
+const castQuadraticVote = `
+local vote = ...
+return box.atomic(function()
+    local poll = box.space.polls:get(vote[1])
+    if poll == nil then
+        return 'not_found', 0
+    end
+    if poll[8] == 'closed' or poll[8] == true then
+        return 'finished', 0
+    end
+    if poll[8] == 'scheduled' or poll[8] == 'draft' then
+        return 'not_open', 0
+    end
+    local cost = 0
+    for _, item in ipairs(vote[6]) do
+        cost = cost + item[2] * item[2]
+    end
+    if cost > (poll[20] or 0) then
+        return 'over_budget', cost
+    end
+    box.space.votes:replace(vote)
+    return 'ok', cost
+end)
+`
+
+func (r *TarantoolRepository) AddQuadraticVote(vote models.Vote) error {
+	log.Printf("Adding quadratic vote for poll %s by user %s: %v", vote.PollID, vote.UserID, vote.Allocation)
+	
+	resp, err := r.conn.Eval(castQuadraticVote, []interface{}{voteToTuple(vote)})
+	if err != nil {
+		log.Printf("ERROR: Failed to add quadratic vote: %v", err)
+		return fmt.Errorf("failed to add vote: %w", err)
+	}
+	
+	if len(resp.Data) < 2 {
+		return fmt.Errorf("failed to add vote: unexpected response %v", resp.Data)
+	}
+	
+	status, _ := resp.Data[0].(string)
+	cost := toInt(resp.Data[1])
+	
+	switch status {
+	case "ok":
+		log.Printf("Quadratic vote added successfully, cost %d", cost)
+		return nil
+	case "over_budget":
+		return fmt.Errorf("%w: cost %d", ErrBudgetExceeded, cost)
+	case "finished":
+		return ErrPollFinished
+	case "not_open":
+		return ErrPollNotOpen
+	default:
+		return ErrPollNotFound
+	}
+}
+
+func (r *TarantoolRepository) RemoveVote(pollID, userID string) error {
+	log.Printf("Removing vote for poll %s by user %s", pollID, userID)
+	
+	resp, err := r.conn.Delete("votes", "user_poll", []interface{}{userID, pollID})
+	if err != nil {
+		log.Printf("ERROR: Failed to remove vote: %v", err)
+		return fmt.Errorf("failed to remove vote: %w", err)
+	}
+	
+	if len(resp.Data) == 0 {
+		log.Printf("No vote found for poll %s by user %s", pollID, userID)
+		return ErrVoteNotFound
+	}
+	
+	log.Printf("Vote removed successfully: %v", resp)
+	return nil
+}
+
 func (r *TarantoolRepository) GetVotes(pollID string) ([]models.Vote, error) {
 	log.Printf("Getting votes for poll ID: %s", pollID)
 	
-	resp, err := r.conn.Select("votes", "poll", 0, 100, tarantool.IterEq, []interface{}{pollID})
+	resp, err := r.conn.Select("votes", "poll", 0, math.MaxUint32, tarantool.IterEq, []interface{}{pollID})
 	if err != nil {
 		log.Printf("ERROR: Failed to get votes: %v", err)
 		return nil, fmt.Errorf("failed to get votes: %w", err)
@@ -221,12 +356,7 @@ func (r *TarantoolRepository) GetVotes(pollID string) ([]models.Vote, error) {
 	votes := make([]models.Vote, len(tuples))
 	
 	for i, tuple := range tuples {
-		votes[i] = models.Vote{
-			PollID:    tuple[0].(string),
-			UserID:    tuple[1].(string),
-			OptionIdx: tuple[2].(int),
-			VotedAt:   tuple[3].(time.Time),
-		}
+		votes[i] = tupleToVote(tuple)
 	}
 	
 	return votes, nil
@@ -236,6 +366,9 @@ func (r *TarantoolRepository) GetPollResults(pollID string) (models.PollResults,
 	log.Printf("Getting poll results for ID: %s", pollID)
 	
 	poll, err := r.GetPoll(pollID)
+	if errors.Is(err, ErrPollNotFound) {
+		return r.getArchivedResults(pollID)
+	}
 	if err != nil {
 		log.Printf("ERROR: Failed to get poll for results: %v", err)
 		return models.PollResults{}, fmt.Errorf("failed to get poll for results: %w", err)
@@ -247,27 +380,444 @@ func (r *TarantoolRepository) GetPollResults(pollID string) (models.PollResults,
 		return models.PollResults{}, fmt.Errorf("failed to get votes for results: %w", err)
 	}
 	
-	results := make(map[string]int)
-	for _, option := range poll.Options {
-		results[option] = 0
+	weights, err := r.GetWeights(pollID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get weights for results: %v", err)
+		return models.PollResults{}, fmt.Errorf("failed to get weights for results: %w", err)
+	}
+	
+	pollResults := computeResults(poll, votes, weights)
+	
+	log.Printf("Poll results calculated: %v options, %v votes", len(pollResults.Results), pollResults.TotalVotes)
+	return pollResults, nil
+}
+
+// getArchivedResults считает результаты по голосованию из архива.
+func (r *TarantoolRepository) getArchivedResults(pollID string) (models.PollResults, error) {
+	resp, err := r.conn.Select("polls_archive", "primary", 0, 1, tarantool.IterEq, []interface{}{pollID})
+	if err != nil {
+		log.Printf("ERROR: Failed to get archived poll: %v", err)
+		return models.PollResults{}, fmt.Errorf("failed to get poll for results: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	if len(tuples) == 0 {
+		log.Printf("Poll not found with ID: %s", pollID)
+		return models.PollResults{}, fmt.Errorf("failed to get poll for results: %w", ErrPollNotFound)
+	}
+	snapshot := tupleToSnapshot(tuples[0])
+	
+	pollResults := computeResults(snapshot.Poll, snapshot.Votes, snapshot.Weights)
+	pollResults.Archived = true
+	
+	log.Printf("Archived poll results calculated: %v options, %v votes", len(pollResults.Results), pollResults.TotalVotes)
+	return pollResults, nil
+}
+
+func (r *TarantoolRepository) ListArchivedPolls() ([]PollSnapshot, error) {
+	log.Printf("Listing archived polls")
+	
+	resp, err := r.conn.Select("polls_archive", "primary", 0, math.MaxUint32, tarantool.IterAll, []interface{}{})
+	if err != nil {
+		log.Printf("ERROR: Failed to list archived polls: %v", err)
+		return nil, fmt.Errorf("failed to list archived polls: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	snapshots := make([]PollSnapshot, len(tuples))
+	for i, tuple := range tuples {
+		snapshots[i] = tupleToSnapshot(tuple)
+	}
+	
+	return snapshots, nil
+}
+
+// removePoll удаляет голосование с голосами, весами и журналом действий, а если
+// передано время архивации, сначала сохраняет их в polls_archive. Архив на vinyl, а Tarantool
+// не допускает транзакций на двух движках, поэтому запись в архив идёт
+// отдельно; при сбое между шагами повторный перенос просто перезапишет архив.
+const removePoll = `
+local poll_id, archived_at = ...
+local poll = box.space.polls:get(poll_id)
+if poll == nil then
+    return -1
+end
+local votes = {}
+for _, vote in box.space.votes.index.poll:pairs(poll_id) do
+    table.insert(votes, vote:totable())
+end
+local weights = {}
+for _, weight in box.space.weights:pairs(poll_id) do
+    table.insert(weights, {weight[2], weight[3]})
+end
+local audit = {}
+for _, entry in box.space.audit.index.poll:pairs(poll_id) do
+    table.insert(audit, entry:totable())
+end
+if archived_at ~= nil then
+    box.space.polls_archive:replace({poll_id, poll[5], poll[7], archived_at, poll:totable(), votes, weights, audit})
+end
+box.atomic(function()
+    for _, vote in ipairs(votes) do
+        box.space.votes:delete({vote[1], vote[2]})
+    end
+    for _, weight in ipairs(weights) do
+        box.space.weights:delete({poll_id, weight[1]})
+    end
+    for _, entry in ipairs(audit) do
+        box.space.audit:delete(entry[1])
+    end
+    box.space.polls:delete(poll_id)
+end)
+return #votes
+`
+
+func (r *TarantoolRepository) ArchivePoll(pollID string) (int, error) {
+	log.Printf("Archiving poll with ID: %s", pollID)
+	
+	count, err := r.removePoll(pollID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive poll: %w", err)
+	}
+	
+	log.Printf("Poll archived successfully with %d votes", count)
+	return count, nil
+}
+
+func (r *TarantoolRepository) PurgePoll(pollID string) (int, error) {
+	log.Printf("Purging poll with ID: %s", pollID)
+	
+	count, err := r.removePoll(pollID, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge poll: %w", err)
+	}
+	
+	log.Printf("Poll purged successfully with %d votes", count)
+	return count, nil
+}
+
+func (r *TarantoolRepository) removePoll(pollID string, archivedAt interface{}) (int, error) {
+	resp, err := r.conn.Eval(removePoll, []interface{}{pollID, archivedAt})
+	if err != nil {
+		log.Printf("ERROR: Failed to remove poll: %v", err)
+		return 0, err
+	}
+	
+	if len(resp.Data) < 1 {
+		return 0, fmt.Errorf("unexpected response %v", resp.Data)
+	}
+	
+	count := toInt(resp.Data[0])
+	if count < 0 {
+		return 0, ErrPollNotFound
+	}
+	return count, nil
+}
+
+// restorePoll заменяет голосование со всеми данными. Как и в removePoll,
+// запись в архив на vinyl идёт вне транзакции над спейсами в памяти.
+const restorePoll = `
+local poll, votes, weights, audit, archived_at = ...
+local poll_id = poll[1]
+box.atomic(function()
+    for _, vote in ipairs(box.space.votes.index.poll:select(poll_id)) do
+        box.space.votes:delete({vote[1], vote[2]})
+    end
+    for _, weight in ipairs(box.space.weights:select(poll_id)) do
+        box.space.weights:delete({weight[1], weight[2]})
+    end
+    for _, entry in ipairs(box.space.audit.index.poll:select(poll_id)) do
+        box.space.audit:delete(entry[1])
+    end
+    box.space.polls:delete(poll_id)
+    if archived_at ~= nil then
+        return
+    end
+    box.space.polls:replace(poll)
+    for _, vote in ipairs(votes) do
+        box.space.votes:replace(vote)
+    end
+    for _, weight in ipairs(weights) do
+        box.space.weights:replace({poll_id, weight[1], weight[2]})
+    end
+    for _, entry in ipairs(audit) do
+        box.space.audit:replace(entry)
+    end
+end)
+if archived_at ~= nil then
+    box.space.polls_archive:replace({poll_id, poll[5], poll[7], archived_at, poll, votes, weights, audit})
+else
+    box.space.polls_archive:delete(poll_id)
+end
+return true
+`
+
+func (r *TarantoolRepository) RestorePoll(snapshot PollSnapshot) error {
+	log.Printf("Restoring poll %s with %d votes", snapshot.Poll.ID, len(snapshot.Votes))
+	
+	votes := make([]interface{}, len(snapshot.Votes))
+	for i, vote := range snapshot.Votes {
+		votes[i] = voteToTuple(vote)
+	}
+	
+	weights := make([]interface{}, 0, len(snapshot.Weights))
+	for userID, weight := range snapshot.Weights {
+		weights = append(weights, []interface{}{userID, weight})
+	}
+	
+	audit := make([]interface{}, len(snapshot.Audit))
+	for i, entry := range snapshot.Audit {
+		audit[i] = auditToTuple(entry)
+	}
+	
+	var archivedAt interface{}
+	if !snapshot.ArchivedAt.IsZero() {
+		archivedAt = snapshot.ArchivedAt
+	}
+	
+	_, err := r.conn.Eval(restorePoll, []interface{}{pollToTuple(snapshot.Poll), votes, weights, audit, archivedAt})
+	if err != nil {
+		log.Printf("ERROR: Failed to restore poll: %v", err)
+		return fmt.Errorf("failed to restore poll: %w", err)
 	}
 	
-	voters := make(map[string]string)
+	log.Printf("Poll restored successfully")
+	return nil
+}
+
+func (r *TarantoolRepository) RemoveOptionVotes(pollID string, optionID int) ([]string, error) {
+	log.Printf("Removing votes for option %d in poll %s", optionID, pollID)
+	
+	votes, err := r.GetVotes(pollID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get votes for option: %w", err)
+	}
 	
+	var userIDs []string
 	for _, vote := range votes {
-		if vote.OptionIdx >= 0 && vote.OptionIdx < len(poll.Options) {
-			option := poll.Options[vote.OptionIdx]
-			results[option]++
-			voters[vote.UserID] = option
+		if vote.OptionID != optionID {
+			continue
+		}
+		
+		if _, err := r.conn.Delete("votes", "primary", []interface{}{vote.PollID, vote.UserID}); err != nil {
+			log.Printf("ERROR: Failed to remove vote of user %s: %v", vote.UserID, err)
+			return userIDs, fmt.Errorf("failed to remove option votes: %w", err)
 		}
+		userIDs = append(userIDs, vote.UserID)
 	}
 	
-	log.Printf("Poll results calculated: %v options, %v votes", len(results), len(voters))
-	return models.PollResults{
-		Poll:    poll,
-		Results: results,
-		Voters:  voters,
-	}, nil
+	log.Printf("Removed %d votes for option %d in poll %s", len(userIDs), optionID, pollID)
+	return userIDs, nil
+}
+
+func (r *TarantoolRepository) SetWeight(pollID, userID string, weight int) error {
+	log.Printf("Setting weight %d for user %s in poll %s", weight, userID, pollID)
+	
+	resp, err := r.conn.Replace("weights", []interface{}{pollID, userID, weight})
+	if err != nil {
+		log.Printf("ERROR: Failed to set weight: %v", err)
+		return fmt.Errorf("failed to set weight: %w", err)
+	}
+	
+	log.Printf("Weight set successfully: %v", resp)
+	return nil
+}
+
+func (r *TarantoolRepository) GetWeights(pollID string) (map[string]int, error) {
+	log.Printf("Getting weights for poll ID: %s", pollID)
+	
+	resp, err := r.conn.Select("weights", "primary", 0, math.MaxUint32, tarantool.IterEq, []interface{}{pollID})
+	if err != nil {
+		log.Printf("ERROR: Failed to get weights: %v", err)
+		return nil, fmt.Errorf("failed to get weights: %w", err)
+	}
+	
+	weights := make(map[string]int)
+	for _, tuple := range resp.Tuples() {
+		weights[tuple[1].(string)] = toInt(tuple[2])
+	}
+	
+	return weights, nil
+}
+
+func (r *TarantoolRepository) AddAuditEntry(entry models.AuditEntry) error {
+	log.Printf("Adding audit entry for poll %s: %s", entry.PollID, entry.Action)
+	
+	resp, err := r.conn.Insert("audit", auditToTuple(entry))
+	
+	if err != nil {
+		log.Printf("ERROR: Failed to add audit entry: %v", err)
+		return fmt.Errorf("failed to add audit entry: %w", err)
+	}
+	
+	log.Printf("Audit entry added successfully: %v", resp)
+	return nil
+}
+
+func (r *TarantoolRepository) GetAuditEntries(pollID string) ([]models.AuditEntry, error) {
+	log.Printf("Getting audit entries for poll ID: %s", pollID)
+	
+	resp, err := r.conn.Select("audit", "poll", 0, math.MaxUint32, tarantool.IterEq, []interface{}{pollID})
+	if err != nil {
+		log.Printf("ERROR: Failed to get audit entries: %v", err)
+		return nil, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	entries := make([]models.AuditEntry, len(tuples))
+	for i, tuple := range tuples {
+		entries[i] = tupleToAudit(tuple)
+	}
+	
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	
+	return entries, nil
+}
+
+func (r *TarantoolRepository) CreateSchedule(schedule models.Schedule) error {
+	log.Printf("Creating schedule %s (%s) in channel %s", schedule.ID, schedule.Spec, schedule.ChannelID)
+	
+	resp, err := r.conn.Insert("schedules", scheduleToTuple(schedule))
+	if err != nil {
+		log.Printf("ERROR: Failed to create schedule: %v", err)
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	
+	log.Printf("Schedule created successfully: %v", resp)
+	return nil
+}
+
+func (r *TarantoolRepository) GetSchedule(scheduleID string) (models.Schedule, error) {
+	log.Printf("Getting schedule with ID: %s", scheduleID)
+	
+	resp, err := r.conn.Select("schedules", "primary", 0, 1, tarantool.IterEq, []interface{}{scheduleID})
+	if err != nil {
+		log.Printf("ERROR: Failed to get schedule: %v", err)
+		return models.Schedule{}, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	if len(tuples) == 0 {
+		log.Printf("Schedule not found with ID: %s", scheduleID)
+		return models.Schedule{}, fmt.Errorf("schedule not found")
+	}
+	
+	return tupleToSchedule(tuples[0]), nil
+}
+
+func (r *TarantoolRepository) UpdateSchedule(schedule models.Schedule) error {
+	log.Printf("Updating schedule %s, next run at %s", schedule.ID, schedule.NextRunAt)
+	
+	resp, err := r.conn.Replace("schedules", scheduleToTuple(schedule))
+	if err != nil {
+		log.Printf("ERROR: Failed to update schedule: %v", err)
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+	
+	log.Printf("Schedule updated successfully: %v", resp)
+	return nil
+}
+
+func (r *TarantoolRepository) DeleteSchedule(scheduleID string) error {
+	log.Printf("Deleting schedule with ID: %s", scheduleID)
+	
+	resp, err := r.conn.Delete("schedules", "primary", []interface{}{scheduleID})
+	if err != nil {
+		log.Printf("ERROR: Failed to delete schedule: %v", err)
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	
+	log.Printf("Schedule deleted successfully: %v", resp)
+	return nil
+}
+
+func (r *TarantoolRepository) ListSchedules() ([]models.Schedule, error) {
+	log.Printf("Listing schedules")
+	
+	resp, err := r.conn.Select("schedules", "primary", 0, math.MaxUint32, tarantool.IterAll, []interface{}{})
+	if err != nil {
+		log.Printf("ERROR: Failed to list schedules: %v", err)
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	schedules := make([]models.Schedule, len(tuples))
+	for i, tuple := range tuples {
+		schedules[i] = tupleToSchedule(tuple)
+	}
+	
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.Before(schedules[j].CreatedAt)
+	})
+	
+	return schedules, nil
+}
+
+func (r *TarantoolRepository) AddDeadLetter(letter models.DeadLetter) error {
+	log.Printf("Adding dead letter %s for %s (%s)", letter.ID, letter.URL, letter.EventType)
+	
+	resp, err := r.conn.Insert("dead_letters", []interface{}{
+		letter.ID,
+		letter.URL,
+		letter.EventType,
+		letter.Payload,
+		letter.Attempts,
+		letter.LastError,
+		letter.FailedAt,
+	})
+	
+	if err != nil {
+		log.Printf("ERROR: Failed to add dead letter: %v", err)
+		return fmt.Errorf("failed to add dead letter: %w", err)
+	}
+	
+	log.Printf("Dead letter added successfully: %v", resp)
+	return nil
+}
+
+func (r *TarantoolRepository) ListDeadLetters() ([]models.DeadLetter, error) {
+	log.Printf("Listing dead letters")
+	
+	resp, err := r.conn.Select("dead_letters", "primary", 0, math.MaxUint32, tarantool.IterAll, []interface{}{})
+	if err != nil {
+		log.Printf("ERROR: Failed to list dead letters: %v", err)
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	
+	tuples := resp.Tuples()
+	letters := make([]models.DeadLetter, len(tuples))
+	for i, tuple := range tuples {
+		letters[i] = models.DeadLetter{
+			ID:        tuple[0].(string),
+			URL:       tuple[1].(string),
+			EventType: tuple[2].(string),
+			Payload:   tuple[3].(string),
+			Attempts:  toInt(tuple[4]),
+			LastError: tuple[5].(string),
+			FailedAt:  tuple[6].(time.Time),
+		}
+	}
+	
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].FailedAt.Before(letters[j].FailedAt)
+	})
+	
+	return letters, nil
+}
+
+func (r *TarantoolRepository) DeleteDeadLetter(letterID string) error {
+	log.Printf("Deleting dead letter with ID: %s", letterID)
+	
+	resp, err := r.conn.Delete("dead_letters", "primary", []interface{}{letterID})
+	if err != nil {
+		log.Printf("ERROR: Failed to delete dead letter: %v", err)
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	
+	log.Printf("Dead letter deleted successfully: %v", resp)
+	return nil
 }
 
 func (r *TarantoolRepository) HealthCheck() error {
@@ -281,4 +831,348 @@ func (r *TarantoolRepository) HealthCheck() error {
 	
 	log.Printf("Health check successful: %v", resp)
 	return nil
+}
+
+func voteToTuple(vote models.Vote) []interface{} {
+	allocation := make([]interface{}, 0, len(vote.Allocation))
+	for optionID, votes := range vote.Allocation {
+		allocation = append(allocation, []interface{}{optionID, votes})
+	}
+	
+	return []interface{}{
+		vote.PollID,
+		vote.UserID,
+		vote.OptionID,
+		vote.VotedAt,
+		vote.Weight,
+		allocation,
+		vote.Ranking,
+		vote.Approved,
+	}
+}
+
+func tupleToVote(tuple []interface{}) models.Vote {
+	vote := models.Vote{
+		PollID:   tuple[0].(string),
+		UserID:   tuple[1].(string),
+		OptionID: toInt(tuple[2]),
+		VotedAt:  tuple[3].(time.Time),
+	}
+	if len(tuple) > 4 {
+		vote.Weight = toInt(tuple[4])
+	}
+	if len(tuple) > 5 {
+		if items, ok := tuple[5].([]interface{}); ok && len(items) > 0 {
+			vote.Allocation = make(map[int]int, len(items))
+			for _, item := range items {
+				pair := item.([]interface{})
+				vote.Allocation[toInt(pair[0])] = toInt(pair[1])
+			}
+		}
+	}
+	if len(tuple) > 6 {
+		vote.Ranking = decodeInts(tuple[6])
+	}
+	if len(tuple) > 7 {
+		vote.Approved = decodeInts(tuple[7])
+	}
+	
+	return vote
+}
+
+func auditToTuple(entry models.AuditEntry) []interface{} {
+	return []interface{}{
+		entry.ID,
+		entry.PollID,
+		entry.UserID,
+		entry.Action,
+		entry.Details,
+		entry.CreatedAt,
+	}
+}
+
+func tupleToAudit(tuple []interface{}) models.AuditEntry {
+	return models.AuditEntry{
+		ID:        tuple[0].(string),
+		PollID:    tuple[1].(string),
+		UserID:    tuple[2].(string),
+		Action:    tuple[3].(string),
+		Details:   tuple[4].(string),
+		CreatedAt: tuple[5].(time.Time),
+	}
+}
+
+// tupleToSnapshot разбирает запись polls_archive. Журнал в архиве
+// появился позже, поэтому у старых записей его может не быть.
+func tupleToSnapshot(tuple []interface{}) PollSnapshot {
+	snapshot := PollSnapshot{
+		Poll:       tupleToPoll(tuple[4].([]interface{})),
+		Weights:    make(map[string]int),
+		ArchivedAt: tuple[3].(time.Time),
+	}
+	
+	for _, raw := range tuple[5].([]interface{}) {
+		snapshot.Votes = append(snapshot.Votes, tupleToVote(raw.([]interface{})))
+	}
+	
+	for _, raw := range tuple[6].([]interface{}) {
+		pair := raw.([]interface{})
+		snapshot.Weights[pair[0].(string)] = toInt(pair[1])
+	}
+	
+	if len(tuple) > 7 {
+		if entries, ok := tuple[7].([]interface{}); ok {
+			for _, raw := range entries {
+				snapshot.Audit = append(snapshot.Audit, tupleToAudit(raw.([]interface{})))
+			}
+		}
+	}
+	
+	return snapshot
+}
+
+func scheduleToTuple(schedule models.Schedule) []interface{} {
+	return []interface{}{
+		schedule.ID,
+		schedule.CreatorID,
+		schedule.ChannelID,
+		schedule.Spec,
+		schedule.Args,
+		schedule.CreatedAt,
+		schedule.NextRunAt,
+		schedule.LastRunAt,
+		schedule.LastPoll,
+	}
+}
+
+func tupleToSchedule(tuple []interface{}) models.Schedule {
+	schedule := models.Schedule{
+		ID:        tuple[0].(string),
+		CreatorID: tuple[1].(string),
+		ChannelID: tuple[2].(string),
+		Spec:      tuple[3].(string),
+		Args:      decodeStrings(tuple[4]),
+		CreatedAt: tuple[5].(time.Time),
+		NextRunAt: tuple[6].(time.Time),
+	}
+	if len(tuple) > 7 && tuple[7] != nil {
+		schedule.LastRunAt = tuple[7].(time.Time)
+	}
+	if len(tuple) > 8 {
+		schedule.LastPoll, _ = tuple[8].(string)
+	}
+	
+	return schedule
+}
+
+func pollToTuple(poll models.Poll) []interface{} {
+	options := make([]interface{}, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = []interface{}{option.ID, option.Text}
+	}
+	
+	return []interface{}{
+		poll.ID,
+		poll.Title,
+		options,
+		poll.CreatorID,
+		poll.ChannelID,
+		poll.CreatedAt,
+		poll.FinishedAt,
+		poll.Status,
+		poll.PostID,
+		poll.ClosesAt,
+		poll.EligibleVoters,
+		poll.EligibleGroups,
+		[]interface{}{poll.Quorum.Count, poll.Quorum.Percent},
+		poll.Threshold,
+		poll.Electorate,
+		poll.TieBreak,
+		poll.TieSeed,
+		poll.CastingVote,
+		poll.Kind,
+		poll.Credits,
+		poll.Seats,
+		poll.OpensAt,
+		encodeDurations(poll.Reminders),
+		poll.ReminderMode,
+		encodeDurations(poll.RemindersSent),
+		poll.Anonymous,
+	}
+}
+
+func tupleToPoll(tuple []interface{}) models.Poll {
+	var poll models.Poll
+	poll.ID = tuple[0].(string)
+	poll.Title = tuple[1].(string)
+	poll.Options = decodeOptions(tuple[2].([]interface{}))
+	poll.CreatorID = tuple[3].(string)
+	poll.ChannelID = tuple[4].(string)
+	poll.CreatedAt = tuple[5].(time.Time)
+	if tuple[6] != nil {
+		poll.FinishedAt = tuple[6].(time.Time)
+	}
+	poll.Status = decodeStatus(tuple[7])
+	poll.PostID = tuple[8].(string)
+	if len(tuple) > 9 && tuple[9] != nil {
+		poll.ClosesAt = tuple[9].(time.Time)
+	}
+	if len(tuple) > 11 {
+		poll.EligibleVoters = decodeStrings(tuple[10])
+		poll.EligibleGroups = decodeStrings(tuple[11])
+	}
+	if len(tuple) > 14 {
+		if quorum, ok := tuple[12].([]interface{}); ok && len(quorum) == 2 {
+			poll.Quorum = models.Quorum{Count: toInt(quorum[0]), Percent: toFloat(quorum[1])}
+		}
+		poll.Threshold, _ = tuple[13].(string)
+		poll.Electorate = toInt(tuple[14])
+	}
+	if len(tuple) > 17 {
+		poll.TieBreak, _ = tuple[15].(string)
+		poll.TieSeed = int64(toInt(tuple[16]))
+		if tuple[17] != nil {
+			castingVote := toInt(tuple[17])
+			poll.CastingVote = &castingVote
+		}
+	}
+	if len(tuple) > 18 {
+		poll.Kind, _ = tuple[18].(string)
+	}
+	if len(tuple) > 19 {
+		poll.Credits = toInt(tuple[19])
+	}
+	if len(tuple) > 20 {
+		poll.Seats = toInt(tuple[20])
+	}
+	if len(tuple) > 21 && tuple[21] != nil {
+		poll.OpensAt = tuple[21].(time.Time)
+	}
+	if len(tuple) > 24 {
+		poll.Reminders = decodeDurations(tuple[22])
+		poll.ReminderMode, _ = tuple[23].(string)
+		poll.RemindersSent = decodeDurations(tuple[24])
+	}
+	if len(tuple) > 25 {
+		poll.Anonymous, _ = tuple[25].(bool)
+	}
+	
+	return poll
+}
+
+// decodeOptions поддерживает старый формат, где варианты хранились строками
+// и идентификатором служила позиция в списке.
+func decodeOptions(raw []interface{}) []models.Option {
+	options := make([]models.Option, len(raw))
+	for i, item := range raw {
+		switch v := item.(type) {
+		case string:
+			options[i] = models.Option{ID: i, Text: v}
+		case []interface{}:
+			options[i] = models.Option{ID: toInt(v[0]), Text: v[1].(string)}
+		}
+	}
+	return options
+}
+
+// decodeStatus поддерживает старый формат, где вместо состояния
+// хранился флаг is_finished.
+func decodeStatus(raw interface{}) string {
+	switch value := raw.(type) {
+	case string:
+		return value
+	case bool:
+		if value {
+			return models.PollStatusClosed
+		}
+	}
+	return models.PollStatusOpen
+}
+
+func decodeStrings(raw interface{}) []string {
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func decodeInts(raw interface{}) []int {
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	
+	values := make([]int, len(items))
+	for i, item := range items {
+		values[i] = toInt(item)
+	}
+	return values
+}
+
+// encodeDurations хранит длительности в секундах.
+func encodeDurations(durations []time.Duration) []int64 {
+	seconds := make([]int64, len(durations))
+	for i, d := range durations {
+		seconds[i] = int64(d / time.Second)
+	}
+	return seconds
+}
+
+func decodeDurations(raw interface{}) []time.Duration {
+	seconds := decodeInts(raw)
+	if len(seconds) == 0 {
+		return nil
+	}
+	
+	durations := make([]time.Duration, len(seconds))
+	for i, s := range seconds {
+		durations[i] = time.Duration(s) * time.Second
+	}
+	return durations
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	}
+	return float64(toInt(v))
+}
+
+func toInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int8:
+		return int(n)
+	case int16:
+		return int(n)
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case uint:
+		return int(n)
+	case uint8:
+		return int(n)
+	case uint16:
+		return int(n)
+	case uint32:
+		return int(n)
+	case uint64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return 0
 }
\ No newline at end of file
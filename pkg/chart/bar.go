@@ -0,0 +1,117 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Bar — одна полоса диаграммы. Встроенный шрифт содержит только ASCII,
+// поэтому подписью служит номер варианта, а текст варианта остаётся в сообщении.
+type Bar struct {
+	Label string
+	Value int
+}
+
+const (
+	width      = 640
+	rowHeight  = 28
+	barHeight  = 18
+	padding    = 12
+	labelWidth = 48
+	valueWidth = 120
+)
+
+var (
+	background = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	textColor  = color.RGBA{R: 0x3d, G: 0x3c, B: 0x40, A: 0xff}
+	trackColor = color.RGBA{R: 0xee, G: 0xee, B: 0xf0, A: 0xff}
+	palette    = []color.RGBA{
+		{R: 0x1c, G: 0x58, B: 0xd9, A: 0xff},
+		{R: 0x3d, G: 0xb8, B: 0x87, A: 0xff},
+		{R: 0xff, G: 0xbc, B: 0x1f, A: 0xff},
+		{R: 0xd2, G: 0x4b, B: 0x4e, A: 0xff},
+		{R: 0x8a, G: 0x5c, B: 0xd6, A: 0xff},
+		{R: 0x26, G: 0xa9, B: 0xc6, A: 0xff},
+	}
+)
+
+// RenderBars рисует горизонтальную столбчатую диаграмму в PNG.
+// Результат детерминирован: одинаковые данные дают одинаковые байты.
+func RenderBars(bars []Bar) ([]byte, error) {
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no bars to render")
+	}
+	
+	height := padding*2 + rowHeight*len(bars)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	
+	total, max := 0, 0
+	for _, bar := range bars {
+		total += bar.Value
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+	
+	trackWidth := width - padding*2 - labelWidth - valueWidth
+	
+	for i, bar := range bars {
+		top := padding + i*rowHeight + (rowHeight-barHeight)/2
+		left := padding + labelWidth
+		
+		drawText(img, fitLabel(bar.Label), padding, top+barHeight-4)
+		
+		track := image.Rect(left, top, left+trackWidth, top+barHeight)
+		draw.Draw(img, track, image.NewUniform(trackColor), image.Point{}, draw.Src)
+		
+		if max > 0 && bar.Value > 0 {
+			filled := trackWidth * bar.Value / max
+			if filled < 2 {
+				filled = 2
+			}
+			fill := image.Rect(left, top, left+filled, top+barHeight)
+			draw.Draw(img, fill, image.NewUniform(palette[i%len(palette)]), image.Point{}, draw.Src)
+		}
+		
+		value := fmt.Sprintf("%d", bar.Value)
+		if total > 0 {
+			value = fmt.Sprintf("%d (%.1f%%)", bar.Value, float64(bar.Value)/float64(total)*100)
+		}
+		drawText(img, value, left+trackWidth+padding, top+barHeight-4)
+	}
+	
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fitLabel обрезает подпись, чтобы она не заходила на полосу.
+func fitLabel(label string) string {
+	maxRunes := (labelWidth - padding/2) / basicfont.Face7x13.Advance
+	runes := []rune(label)
+	if len(runes) <= maxRunes {
+		return label
+	}
+	return string(runes[:maxRunes-1]) + "~"
+}
+
+func drawText(img draw.Image, text string, x, y int) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(textColor),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package chart
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "перезаписать эталонные PNG в testdata")
+
+func TestRenderBarsGolden(t *testing.T) {
+	many := make([]Bar, 15)
+	for i := range many {
+		many[i] = Bar{Label: fmt.Sprintf("%d.", i+1), Value: (i * 7) % 11}
+	}
+	
+	tests := []struct {
+		name string
+		bars []Bar
+	}{
+		{"empty", []Bar{{Label: "1.", Value: 0}, {Label: "2.", Value: 0}, {Label: "3.", Value: 0}}},
+		{"single", []Bar{{Label: "1.", Value: 5}}},
+		{"long_labels", []Bar{{Label: "Pizza with pineapple", Value: 3}, {Label: "1234567890", Value: 1}, {Label: "Sushi", Value: 2}}},
+		{"many", many},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderBars(tt.bars)
+			if err != nil {
+				t.Fatalf("RenderBars: %v", err)
+			}
+			
+			golden := filepath.Join("testdata", tt.name+".png")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("chart differs from %s; run go test ./pkg/chart -update and review the image", golden)
+			}
+		})
+	}
+}
+
+func TestRenderBarsNoBars(t *testing.T) {
+	if _, err := RenderBars(nil); err == nil {
+		t.Fatal("expected an error for an empty bar list")
+	}
+}
+
+func TestFitLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		want  string
+	}{
+		{"1.", "1."},
+		{"123456", "123456"},
+		{"1234567", "12345~"},
+		{"Pizza with pineapple", "Pizza~"},
+	}
+	
+	for _, tt := range tests {
+		if got := fitLabel(tt.label); got != tt.want {
+			t.Errorf("fitLabel(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
\ No newline at end of file